@@ -0,0 +1,77 @@
+package numcsv
+
+import (
+	"io"
+	"os"
+)
+
+// FileWriter is a Writer to a file. Close must be called to flush the records
+// and close the file.
+type FileWriter struct {
+	*Writer
+	f *os.File
+}
+
+// Close flushes the records and closes the file
+func (w *FileWriter) Close() error {
+	err := w.Flush()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// OpenAppend opens the CSV file at path for appending records. If the file
+// does not exist or is empty, it is created and the headings are written.
+// Otherwise the headings of the file are read as by Reader.ReadHeading, and
+// ErrHeadingMismatch is returned if they are not the same as headings. The
+// file is opened with O_APPEND, so that records written by other processes
+// appending to the file are not overwritten.
+func OpenAppend(path string, headings []string) (*FileWriter, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	w, err := openAppend(f, headings)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func openAppend(f *os.File, headings []string) (*FileWriter, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	w := &FileWriter{Writer: NewWriter(f), f: f}
+	if info.Size() == 0 {
+		return w, w.WriteHeading(headings)
+	}
+
+	existing, err := NewReader(io.NewSectionReader(f, 0, info.Size())).ReadHeading()
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) != len(headings) {
+		return nil, ErrHeadingMismatch
+	}
+	for i, h := range existing {
+		if h != headings[i] {
+			return nil, ErrHeadingMismatch
+		}
+	}
+
+	// Start the records on a new line if the file does not end with one
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return nil, err
+	}
+	if last[0] != '\n' && last[0] != '\r' {
+		if err := w.newline(); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,89 @@
+package numcsv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.csv")
+	headings := []string{"t", "v"}
+	for _, record := range [][]float64{{1, 10}, {2, 20}} {
+		w, err := OpenAppend(path, headings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file which does not end with a newline
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("3,30")
+	f.Close()
+	w, err := OpenAppend(path, headings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]float64{4, 40})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gotHeadings, data, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeadings, headings) {
+		t.Errorf("headings = %q, want %q", gotHeadings, headings)
+	}
+	if want := [][]float64{{1, 10}, {2, 20}, {3, 30}, {4, 40}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+
+	if _, err := OpenAppend(path, []string{"t", "w"}); !errors.Is(err, ErrHeadingMismatch) {
+		t.Errorf("other headings: err = %v, want ErrHeadingMismatch", err)
+	}
+}
+
+func TestOpenAppendConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.csv")
+	headings := []string{"v"}
+	a, err := OpenAppend(path, headings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	a, err = OpenAppend(path, headings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenAppend(path, headings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each flush is written at the end of the file as it is then
+	a.Write([]float64{1})
+	a.Flush()
+	b.Write([]float64{2})
+	b.Flush()
+	a.Write([]float64{3})
+	a.Close()
+	b.Close()
+	_, data, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1}, {2}, {3}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
@@ -0,0 +1,141 @@
+// package arrowcsv converts between the records read by numcsv and Apache
+// Arrow record batches and Parquet files, so that numcsv can be used to
+// load messy csv files into a columnar pipeline. It is a separate package so
+// that numcsv itself does not depend on Arrow.
+
+package arrowcsv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// NewRecordBatch returns the records as a record batch with a float64 column
+// for each heading. Missing (NaN) values are stored as nulls. If headings is
+// nil, the columns are named c0, c1, ...
+func NewRecordBatch(mem memory.Allocator, headings []string, data [][]float64) arrow.RecordBatch {
+	cols := len(headings)
+	if headings == nil && len(data) > 0 {
+		cols = len(data[0])
+	}
+	fields := make([]arrow.Field, cols)
+	for j := range fields {
+		name := "c" + strconv.Itoa(j)
+		if headings != nil {
+			name = headings[j]
+		}
+		fields[j] = arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+	}
+	b := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+	defer b.Release()
+	for j := range fields {
+		fb := b.Field(j).(*array.Float64Builder)
+		fb.Reserve(len(data))
+		for _, record := range data {
+			if math.IsNaN(record[j]) {
+				fb.AppendNull()
+			} else {
+				fb.Append(record[j])
+			}
+		}
+	}
+	return b.NewRecordBatch()
+}
+
+// FromRecordBatch returns the headings and records of a record batch. The
+// columns must be floating point or integer, and nulls are read as NaN.
+func FromRecordBatch(rec arrow.RecordBatch) (headings []string, data [][]float64, err error) {
+	n := int(rec.NumRows())
+	c := int(rec.NumCols())
+	raw := make([]float64, n*c)
+	for j := 0; j < c; j++ {
+		headings = append(headings, rec.ColumnName(j))
+		if err := copyColumn(raw[j:], c, rec.Column(j)); err != nil {
+			return nil, nil, fmt.Errorf("column %q: %w", rec.ColumnName(j), err)
+		}
+	}
+	return headings, rows(raw, n, c), nil
+}
+
+// WriteParquet writes the records as a Parquet file with a float64 column
+// for each heading, as in NewRecordBatch.
+func WriteParquet(w io.Writer, headings []string, data [][]float64) error {
+	rec := NewRecordBatch(memory.DefaultAllocator, headings, data)
+	defer rec.Release()
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), w, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+	return fw.Close()
+}
+
+// ReadParquet reads the headings and records of a Parquet file whose columns
+// are floating point or integer. Nulls are read as NaN.
+func ReadParquet(r parquet.ReaderAtSeeker) (headings []string, data [][]float64, err error) {
+	tbl, err := pqarrow.ReadTable(context.Background(), r, nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tbl.Release()
+	n := int(tbl.NumRows())
+	c := int(tbl.NumCols())
+	raw := make([]float64, n*c)
+	for j := 0; j < c; j++ {
+		col := tbl.Column(j)
+		headings = append(headings, col.Name())
+		off := 0
+		for _, chunk := range col.Data().Chunks() {
+			if err := copyColumn(raw[off*c+j:], c, chunk); err != nil {
+				return nil, nil, fmt.Errorf("column %q: %w", col.Name(), err)
+			}
+			off += chunk.Len()
+		}
+	}
+	return headings, rows(raw, n, c), nil
+}
+
+// copyColumn copies the values of arr into dst with the given stride
+func copyColumn(dst []float64, stride int, arr arrow.Array) error {
+	var at func(i int) float64
+	switch a := arr.(type) {
+	case *array.Float64:
+		at = func(i int) float64 { return a.Value(i) }
+	case *array.Float32:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int64:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int32:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	default:
+		return fmt.Errorf("unsupported type %s", arr.DataType())
+	}
+	for i := 0; i < arr.Len(); i++ {
+		v := math.NaN()
+		if arr.IsValid(i) {
+			v = at(i)
+		}
+		dst[i*stride] = v
+	}
+	return nil
+}
+
+func rows(raw []float64, n, c int) [][]float64 {
+	data := make([][]float64, n)
+	for i := range data {
+		data[i] = raw[i*c : (i+1)*c]
+	}
+	return data
+}
@@ -0,0 +1,68 @@
+package arrowcsv
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// equal returns whether a and b have the same shape and values, with NaN
+// equal to NaN
+func equal(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j, v := range a[i] {
+			w := b[i][j]
+			if v != w && !(math.IsNaN(v) && math.IsNaN(w)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	headings := []string{"a", "b"}
+	data := [][]float64{{1, math.NaN()}, {-2.5, 1e300}, {0, 3}}
+	var b bytes.Buffer
+	if err := WriteParquet(&b, headings, data); err != nil {
+		t.Fatal(err)
+	}
+	gotHeadings, got, err := ReadParquet(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeadings, headings) {
+		t.Errorf("headings = %q, want %q", gotHeadings, headings)
+	}
+	if !equal(got, data) {
+		t.Errorf("data = %v, want %v", got, data)
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	data := [][]float64{{1, 2}, {math.NaN(), 4}}
+	rec := NewRecordBatch(memory.DefaultAllocator, nil, data)
+	defer rec.Release()
+	if rec.Column(0).NullN() != 1 {
+		t.Errorf("nulls = %d, want 1", rec.Column(0).NullN())
+	}
+	headings, got, err := FromRecordBatch(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c0", "c1"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if !equal(got, data) {
+		t.Errorf("data = %v, want %v", got, data)
+	}
+}
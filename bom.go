@@ -0,0 +1,111 @@
+package numcsv
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// bomReader removes a UTF-8 byte-order mark from the start of the input, and
+// decodes the input from UTF-16 if it starts with a UTF-16 byte-order mark,
+// as written by Excel on Windows.
+type bomReader struct {
+	r       *bufio.Reader
+	checked bool
+	utf16   bool
+	big     bool   // UTF-16 is big-endian
+	pending []byte // decoded bytes not yet returned
+	unit    uint16 // UTF-16 unit read after an unpaired surrogate
+	hasUnit bool
+}
+
+func newBOMReader(r io.Reader) *bomReader {
+	return &bomReader{r: bufio.NewReader(r)}
+}
+
+// reset makes the bomReader read from r, keeping its buffer
+func (b *bomReader) reset(r io.Reader) {
+	b.r.Reset(r)
+	b.checked, b.utf16, b.big = false, false, false
+	b.pending = b.pending[:0]
+	b.hasUnit = false
+}
+
+func (b *bomReader) Read(p []byte) (int, error) {
+	if !b.checked {
+		b.checked = true
+		bom, _ := b.r.Peek(3)
+		switch {
+		case len(bom) == 3 && bom[0] == 0xEF && bom[1] == 0xBB && bom[2] == 0xBF:
+			b.r.Discard(3)
+		case len(bom) >= 2 && bom[0] == 0xFF && bom[1] == 0xFE:
+			b.r.Discard(2)
+			b.utf16 = true
+		case len(bom) >= 2 && bom[0] == 0xFE && bom[1] == 0xFF:
+			b.r.Discard(2)
+			b.utf16, b.big = true, true
+		}
+	}
+	if !b.utf16 {
+		return b.r.Read(p)
+	}
+	var err error
+	for len(b.pending) < len(p) && err == nil {
+		var r rune
+		r, err = b.readRune()
+		if err == nil {
+			b.pending = utf8.AppendRune(b.pending, r)
+		}
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	if n > 0 {
+		return n, nil
+	}
+	return 0, err
+}
+
+// readRune reads a UTF-16 code point, which may be a surrogate pair. An
+// unpaired surrogate is read as utf8.RuneError.
+func (b *bomReader) readRune() (rune, error) {
+	u, err := b.readUnit()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(rune(u)) {
+		return rune(u), nil
+	}
+	if u >= 0xDC00 {
+		// A low surrogate without a high surrogate before it
+		return utf8.RuneError, nil
+	}
+	u2, err := b.readUnit()
+	if err != nil {
+		return utf8.RuneError, nil
+	}
+	r := utf16.DecodeRune(rune(u), rune(u2))
+	if r == utf8.RuneError {
+		// Keep the unit after the unpaired surrogate for the next rune
+		b.unit, b.hasUnit = u2, true
+	}
+	return r, nil
+}
+
+func (b *bomReader) readUnit() (uint16, error) {
+	if b.hasUnit {
+		b.hasUnit = false
+		return b.unit, nil
+	}
+	var buf [2]byte
+	if _, err := io.ReadFull(b.r, buf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	if b.big {
+		return uint16(buf[0])<<8 | uint16(buf[1]), nil
+	}
+	return uint16(buf[1])<<8 | uint16(buf[0]), nil
+}
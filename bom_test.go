@@ -0,0 +1,116 @@
+package numcsv
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+// encodeUTF16 encodes s as UTF-16 with a byte-order mark
+func encodeUTF16(s string, big bool) []byte {
+	units := append([]uint16{0xFEFF}, utf16.Encode([]rune(s))...)
+	return encodeUnits(units, big)
+}
+
+func encodeUnits(units []uint16, big bool) []byte {
+	b := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		if big {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+func TestBOMReader(t *testing.T) {
+	text := "a,b\r\n1,é\n2,😀\n"
+	for _, test := range []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"plain", []byte(text), text},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), text},
+		{"utf-8 bom only", []byte{0xEF, 0xBB, 0xBF}, ""},
+		{"partial bom", []byte{0xEF, 0xBB}, "\xEF\xBB"},
+		{"empty", nil, ""},
+		{"utf-16le", encodeUTF16(text, false), text},
+		{"utf-16be", encodeUTF16(text, true), text},
+		{"utf-16le bom only", []byte{0xFF, 0xFE}, ""},
+		{"utf-16le odd trailing byte", append(encodeUTF16("ab", false), 'c'), "ab"},
+		{"unpaired high surrogate", append([]byte{0xFF, 0xFE}, encodeUnits([]uint16{0xD83D, 'a', 'b'}, false)...), "�ab"},
+		{"unpaired low surrogate", append([]byte{0xFF, 0xFE}, encodeUnits([]uint16{0xDE00, 'a'}, false)...), "�a"},
+		{"surrogate at end", append([]byte{0xFE, 0xFF}, encodeUnits([]uint16{'a', 0xD83D}, true)...), "a�"},
+	} {
+		for _, wrap := range []struct {
+			name string
+			f    func(io.Reader) io.Reader
+		}{
+			{"", func(r io.Reader) io.Reader { return r }},
+			{" one byte", iotest.OneByteReader},
+			{" half", iotest.HalfReader},
+		} {
+			got, err := io.ReadAll(newBOMReader(wrap.f(bytes.NewReader(test.input))))
+			if err != nil {
+				t.Errorf("%s%s: %v", test.name, wrap.name, err)
+				continue
+			}
+			if string(got) != test.want {
+				t.Errorf("%s%s: got %q, want %q", test.name, wrap.name, got, test.want)
+			}
+		}
+	}
+}
+
+// The decoded bytes must be returned however small the reads are
+func TestBOMReaderShortReads(t *testing.T) {
+	text := "x,y\n1.5,😀\n"
+	b := newBOMReader(bytes.NewReader(encodeUTF16(text, false)))
+	var got []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := b.Read(buf)
+		got = append(got, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(got) != text {
+		t.Errorf("got %q, want %q", got, text)
+	}
+}
+
+func TestReaderUTF16(t *testing.T) {
+	for _, big := range []bool{false, true} {
+		r := NewReader(bytes.NewReader(encodeUTF16("time,temp\r\n1,20.5\r\n2,21\r\n", big)))
+		headings, data, err := r.ReadAllWithHeading()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"time", "temp"}; !reflect.DeepEqual(headings, want) {
+			t.Errorf("big=%v: headings = %q, want %q", big, headings, want)
+		}
+		if want := [][]float64{{1, 20.5}, {2, 21}}; !reflect.DeepEqual(data, want) {
+			t.Errorf("big=%v: data = %v, want %v", big, data, want)
+		}
+	}
+}
+
+func TestBOMReaderReset(t *testing.T) {
+	b := newBOMReader(bytes.NewReader(encodeUTF16("a", true)))
+	if got, _ := io.ReadAll(b); string(got) != "a" {
+		t.Errorf("got %q, want %q", got, "a")
+	}
+	b.reset(bytes.NewReader([]byte("\xEF\xBB\xBFb")))
+	if got, _ := io.ReadAll(b); string(got) != "b" {
+		t.Errorf("after reset: got %q, want %q", got, "b")
+	}
+}
@@ -0,0 +1,155 @@
+package numcsv
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+)
+
+const cacheMagic = "NUMCSVC1"
+
+var errCache = errors.New("not a numcsv cache file")
+
+// SaveCache writes the headings and records read from the file at source to
+// a binary cache file at path, along with a hash of source. LoadCache reads
+// them back much faster than source can be parsed.
+func SaveCache(path, source string, headings []string, data [][]float64) error {
+	sum, err := hashFile(source)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = writeCache(f, sum, headings, data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// LoadCache reads the headings and records saved by SaveCache. ErrStaleCache
+// is returned if the file at source has changed since the cache was saved.
+func LoadCache(path, source string) (headings []string, data [][]float64, err error) {
+	sum, err := hashFile(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return readCache(f, sum)
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// The cache is the magic, the hash of the source, the number of headings,
+// rows and columns, the length prefixed headings, and then the records as
+// little-endian float64s.
+func writeCache(w io.Writer, sum []byte, headings []string, data [][]float64) error {
+	rows, cols := len(data), len(headings)
+	if rows > 0 {
+		cols = len(data[0])
+	}
+	if rows > 0 && cols == 0 {
+		return ErrFieldCount
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(cacheMagic)
+	bw.Write(sum)
+	for _, n := range []int{len(headings), rows, cols} {
+		binary.Write(bw, binary.LittleEndian, uint64(n))
+	}
+	for _, h := range headings {
+		binary.Write(bw, binary.LittleEndian, uint32(len(h)))
+		bw.WriteString(h)
+	}
+	var buf [8]byte
+	for _, record := range data {
+		if len(record) != cols {
+			return ErrFieldCount
+		}
+		for _, v := range record {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			bw.Write(buf[:])
+		}
+	}
+	return bw.Flush()
+}
+
+func readCache(r io.Reader, sum []byte) ([]string, [][]float64, error) {
+	br := bufio.NewReader(r)
+	head := make([]byte, len(cacheMagic)+sha256.Size)
+	if _, err := io.ReadFull(br, head); err != nil {
+		return nil, nil, err
+	}
+	if string(head[:len(cacheMagic)]) != cacheMagic {
+		return nil, nil, errCache
+	}
+	if !bytes.Equal(head[len(cacheMagic):], sum) {
+		return nil, nil, ErrStaleCache
+	}
+	var dims [3]uint64
+	if err := binary.Read(br, binary.LittleEndian, &dims); err != nil {
+		return nil, nil, err
+	}
+	var headings []string
+	for i := uint64(0); i < dims[0]; i++ {
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, nil, err
+		}
+		// Read the heading into a growing buffer, so a corrupt length
+		// cannot allocate more than the file holds
+		var h bytes.Buffer
+		if _, err := io.CopyN(&h, br, int64(n)); err != nil {
+			return nil, nil, err
+		}
+		headings = append(headings, h.String())
+	}
+	if dims[1] > math.MaxInt || dims[2] > math.MaxInt {
+		return nil, nil, errCache
+	}
+	rows, cols := int(dims[1]), int(dims[2])
+	if (cols > 0 && rows > math.MaxInt/8/cols) || (cols == 0 && rows > 0) {
+		return nil, nil, errCache
+	}
+	// Grow the values as they are read, as for the headings
+	n := rows * cols
+	hint := n
+	if hint > 1<<16 {
+		hint = 1 << 16
+	}
+	raw := make([]float64, 0, hint)
+	var buf [8]byte
+	for k := 0; k < n; k++ {
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			return nil, nil, err
+		}
+		raw = append(raw, math.Float64frombits(binary.LittleEndian.Uint64(buf[:])))
+	}
+	data := make([][]float64, rows)
+	for i := range data {
+		data[i] = raw[i*cols : (i+1)*cols]
+	}
+	return headings, data, nil
+}
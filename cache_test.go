@@ -0,0 +1,85 @@
+package numcsv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(source, []byte("a,b\n1,2\n3,NaN\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "data.cache")
+	headings := []string{"a", "b"}
+	data := [][]float64{{1, 2}, {3, math.NaN()}}
+	if err := SaveCache(path, source, headings, data); err != nil {
+		t.Fatal(err)
+	}
+	gotHeadings, got, err := LoadCache(path, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeadings, headings) {
+		t.Errorf("headings = %q, want %q", gotHeadings, headings)
+	}
+	if !equalBits(got, data) {
+		t.Errorf("data = %v, want %v", got, data)
+	}
+}
+
+func TestCacheStale(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(source, []byte("a\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "data.cache")
+	if err := SaveCache(path, source, []string{"a"}, [][]float64{{1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("a\n2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadCache(path, source); !errors.Is(err, ErrStaleCache) {
+		t.Errorf("err = %v, want ErrStaleCache", err)
+	}
+}
+
+func TestReadCacheCorrupt(t *testing.T) {
+	sum := make([]byte, sha256.Size)
+	// cacheFile returns a cache with the given dimensions, a heading with
+	// the given length and no data
+	cacheFile := func(nh, rows, cols uint64, hlen uint32) []byte {
+		var b bytes.Buffer
+		b.WriteString(cacheMagic)
+		b.Write(sum)
+		binary.Write(&b, binary.LittleEndian, [3]uint64{nh, rows, cols})
+		if nh > 0 {
+			binary.Write(&b, binary.LittleEndian, hlen)
+		}
+		return b.Bytes()
+	}
+	for _, test := range []struct {
+		name string
+		file []byte
+	}{
+		{"long heading", cacheFile(1, 0, 1, math.MaxUint32)},
+		{"overflow", cacheFile(0, 1<<62, 1<<62, 0)},
+		{"too large", cacheFile(0, math.MaxUint64, 1, 0)},
+		{"empty rows", cacheFile(0, 1<<40, 0, 0)},
+		{"truncated data", cacheFile(0, 1<<30, 4, 0)},
+	} {
+		if _, _, err := readCache(bytes.NewReader(test.file), sum); err == nil {
+			t.Errorf("%s: no error", test.name)
+		}
+	}
+}
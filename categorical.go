@@ -0,0 +1,114 @@
+package numcsv
+
+import "fmt"
+
+// CategoricalColumn declares a column containing a small set of string
+// values. Each value is read as its label, the index of the value in
+// Categories. Values not in Categories are appended to it as they are found,
+// unless Strict is set. The categories learned from a file are returned
+// by Reader.Categories, and can be used to label a second file identically.
+// A value that is one of the Reader.NaNTokens is missing. Its label is NaN,
+// which is not replaced by Impute (except ImputeDrop), and its one-hot
+// columns are all 0.
+type CategoricalColumn struct {
+	Name       string // heading of the column. If "", Index is used instead
+	Index      int    // index of the column in the record
+	Categories []string
+	Strict     bool // values not in Categories are an error
+
+	// OneHot expands the column into one column per category in the records
+	// returned by ReadAllSlice and ReadTable, with a 1 in the column of the
+	// value. Read still returns the label.
+	OneHot bool
+}
+
+type catColumn struct {
+	CategoricalColumn
+	col    int // index of the column in the file
+	labels map[string]int
+}
+
+func newCatColumn(c CategoricalColumn, j int) *catColumn {
+	cat := &catColumn{
+		CategoricalColumn: c,
+		col:               j,
+		labels:            make(map[string]int, len(c.Categories)),
+	}
+	cat.Categories = append([]string(nil), c.Categories...)
+	for i, v := range cat.Categories {
+		cat.labels[v] = i
+	}
+	return cat
+}
+
+func (c *catColumn) parse(str string) (float64, error) {
+	label, ok := c.labels[str]
+	if !ok {
+		if c.Strict {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownToken, str)
+		}
+		label = len(c.Categories)
+		c.labels[str] = label
+		c.Categories = append(c.Categories, str)
+	}
+	return float64(label), nil
+}
+
+// Categories returns the categories of each of the CategoricalColumns, in
+// order of their labels, including those learned while reading.
+func (r *Reader) Categories() [][]string {
+	if !r.isSetup {
+		cats := make([][]string, len(r.CategoricalColumns))
+		for i, c := range r.CategoricalColumns {
+			cats[i] = c.Categories
+		}
+		return cats
+	}
+	cats := make([][]string, len(r.cats))
+	for i, c := range r.cats {
+		cats[i] = c.Categories
+	}
+	return cats
+}
+
+// oneHotSpans returns the number of categories of each value of a record that
+// is expanded into one-hot columns, and 0 for the other values. Returns nil
+// if there are no one-hot columns.
+func (r *Reader) oneHotSpans() []int {
+	var span []int
+	for _, c := range r.cats {
+		if !c.OneHot {
+			continue
+		}
+		if span == nil {
+			span = make([]int, r.width)
+		}
+		span[r.outIndex[c.col]] = len(c.Categories)
+	}
+	return span
+}
+
+// matHeadings returns the headings of the columns of the records returned by
+// ReadAllSlice and ReadTable. One-hot columns are named heading=category.
+func (r *Reader) matHeadings() []string {
+	span := r.oneHotSpans()
+	if (span == nil && r.order == nil) || r.dataHeadings == nil {
+		return r.dataHeadings
+	}
+	cats := make(map[int]*catColumn)
+	for _, c := range r.cats {
+		cats[r.outIndex[c.col]] = c
+	}
+	var headings []string
+	for _, j := range r.outColumns() {
+		h := r.dataHeadings[j]
+		if span == nil || span[j] == 0 {
+			headings = append(headings, h)
+			continue
+		}
+		for _, v := range cats[j].Categories {
+			headings = append(headings, h+"="+v)
+		}
+	}
+	return headings
+}
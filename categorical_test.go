@@ -0,0 +1,45 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A missing category has all-zero one-hot columns, and is not imputed
+func TestCategoricalMissing(t *testing.T) {
+	const input = "a,c\n1,low\n2,NA\n3,high\n"
+	for _, impute := range []Impute{ImputeNone, ImputeConstant, ImputeMean, ImputeMedian} {
+		r := NewReader(strings.NewReader(input))
+		r.NaNTokens = []string{"NA"}
+		r.Impute = impute
+		r.CategoricalColumns = []CategoricalColumn{{Name: "c", OneHot: true}}
+		if _, err := r.ReadHeading(); err != nil {
+			t.Fatal(err)
+		}
+		data, mask, err := r.ReadAllMasked()
+		if err != nil {
+			t.Fatalf("impute %d: %v", impute, err)
+		}
+		if want := [][]float64{{1, 1, 0}, {2, 0, 0}, {3, 0, 1}}; !reflect.DeepEqual(data, want) {
+			t.Errorf("impute %d: data = %v, want %v", impute, data, want)
+		}
+		if want := []bool{true, false, false}; !reflect.DeepEqual(mask[1], want) {
+			t.Errorf("impute %d: mask = %v, want %v", impute, mask[1], want)
+		}
+
+		r = NewReader(strings.NewReader(input))
+		r.NaNTokens = []string{"NA"}
+		r.Impute = impute
+		r.CategoricalColumns = []CategoricalColumn{{Name: "c"}}
+		r.ReadHeading()
+		data, err = r.ReadAllSlice()
+		if err != nil {
+			t.Fatalf("impute %d: %v", impute, err)
+		}
+		if !math.IsNaN(data[1][1]) || data[0][1] != 0 || data[2][1] != 1 {
+			t.Errorf("impute %d: labels = %v, want [0 NaN 1]", impute, data)
+		}
+	}
+}
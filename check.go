@@ -0,0 +1,63 @@
+package numcsv
+
+import (
+	"fmt"
+	"math"
+)
+
+// Check is an assertion about the values of a record. Valid is called with
+// the values of Columns for every record, and a record for which it returns
+// false is reported by Read as a *ParseError wrapping ErrCheck.
+type Check struct {
+	Name    string // name of the check used in error messages
+	Columns []Column
+	Valid   func(vals []float64) bool
+}
+
+// SumCheck returns a Check that the value of total is the sum of the values
+// of parts to within tol. Records with a missing (NaN) value in any of the
+// columns pass.
+func SumCheck(name string, tol float64, total Column, parts ...Column) Check {
+	return Check{
+		Name:    name,
+		Columns: append([]Column{total}, parts...),
+		Valid: func(vals []float64) bool {
+			if hasNaN(vals) {
+				return true
+			}
+			var sum float64
+			for _, v := range vals[1:] {
+				sum += v
+			}
+			return math.Abs(vals[0]-sum) <= tol
+		},
+	}
+}
+
+// OrderCheck returns a Check that the value of lo is not greater than the
+// value of hi. Records with a missing (NaN) value in either column pass.
+func OrderCheck(name string, lo, hi Column) Check {
+	return Check{
+		Name:    name,
+		Columns: []Column{lo, hi},
+		Valid: func(vals []float64) bool {
+			return hasNaN(vals) || vals[0] <= vals[1]
+		},
+	}
+}
+
+type check struct {
+	Check
+	idx  []int // indices of the columns in the record
+	vals []float64
+}
+
+func (c *check) check(data []float64) error {
+	for k, j := range c.idx {
+		c.vals[k] = data[j]
+	}
+	if !c.Valid(c.vals) {
+		return fmt.Errorf("%w: %s", ErrCheck, c.Name)
+	}
+	return nil
+}
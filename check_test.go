@@ -0,0 +1,40 @@
+package numcsv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChecks(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		check Check
+		line  int // line of the failing record, or 0 if none fails
+	}{
+		{"t,a,b\n3,1,2\n5,2,3\n", SumCheck("sum", 0, Column{Name: "t"}, Column{Name: "a"}, Column{Name: "b"}), 0},
+		{"t,a,b\n3,1,2\n6,2,3\n", SumCheck("sum", 0, Column{Name: "t"}, Column{Name: "a"}, Column{Name: "b"}), 3},
+		{"t,a,b\n3.05,1,2\n", SumCheck("sum", 0.1, Column{Name: "t"}, Column{Name: "a"}, Column{Name: "b"}), 0},
+		{"t,a,b\nNA,1,2\n3,NA,2\n", SumCheck("sum", 0, Column{Name: "t"}, Column{Name: "a"}, Column{Name: "b"}), 0},
+		{"lo,hi\n1,2\n2,2\n", OrderCheck("order", Column{Name: "lo"}, Column{Name: "hi"}), 0},
+		{"lo,hi\n1,2\n3,2\n", OrderCheck("order", Column{Name: "lo"}, Column{Name: "hi"}), 3},
+		{"lo,hi\nNA,2\n3,NA\n", OrderCheck("order", Column{Name: "lo"}, Column{Name: "hi"}), 0},
+		{"a\n1\n-1\n", Check{Name: "positive", Columns: []Column{{Name: "a"}}, Valid: func(v []float64) bool { return v[0] > 0 }}, 3},
+	} {
+		r := NewReader(strings.NewReader(test.input))
+		r.NaNTokens = []string{"NA"}
+		r.Checks = []Check{test.check}
+		r.ReadHeading()
+		_, err := r.ReadAllSlice()
+		var perr *ParseError
+		switch {
+		case test.line == 0 && err != nil:
+			t.Errorf("%q: %v", test.input, err)
+		case test.line == 0:
+		case !errors.Is(err, ErrCheck) || !errors.As(err, &perr):
+			t.Errorf("%q: err = %v, want ErrCheck", test.input, err)
+		case perr.Line != test.line:
+			t.Errorf("%q: line = %d, want %d", test.input, perr.Line, test.line)
+		}
+	}
+}
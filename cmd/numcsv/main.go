@@ -0,0 +1,274 @@
+// numcsv validates, converts and summarizes numeric csv files using the
+// tolerant parser of the numcsv package.
+//
+// Usage:
+//
+//	numcsv validate [flags] [file]
+//	numcsv convert [flags] [file]
+//	numcsv stats [flags] [file]
+//
+// validate reports every line that cannot be read, and exits with status 1
+// if there are any. convert writes the records to standard output with a new
+// delimiter and precision, optionally keeping only some of the columns. stats
+// prints the count, missing values, minimum, maximum, mean and standard
+// deviation of each column. If file is omitted or "-", standard input is read.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/btracey/numcsv"
+)
+
+const usage = `usage: numcsv <command> [flags] [file]
+
+commands:
+	validate	report the lines that cannot be read
+	convert		rewrite the records with a new delimiter or precision
+	stats		print statistics of each column
+
+Run numcsv <command> -h for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "validate":
+		err = validate(args)
+	case "convert":
+		err = convert(args)
+	case "stats":
+		err = stats(args)
+	case "-h", "-help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "numcsv: unknown command %q\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "numcsv:", err)
+		os.Exit(1)
+	}
+}
+
+// readFlags are the flags shared by the commands for configuring the Reader
+type readFlags struct {
+	comma     string
+	comment   string
+	noHeading bool
+	nan       string
+}
+
+func (rf *readFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&rf.comma, "comma", ",", "field delimiter")
+	fs.StringVar(&rf.comment, "comment", "", "prefix of comment lines")
+	fs.BoolVar(&rf.noHeading, "noheading", false, "the file has no heading line")
+	fs.StringVar(&rf.nan, "nan", "", "comma separated tokens read as missing values")
+}
+
+// open opens the file named by the arguments and returns a Reader for it
+// with the headings read
+func (rf *readFlags) open(fs *flag.FlagSet) (*numcsv.Reader, io.Closer, error) {
+	var f *os.File
+	switch name := fs.Arg(0); {
+	case fs.NArg() > 1:
+		return nil, nil, errors.New("too many arguments")
+	case name == "" || name == "-":
+		f = os.Stdin
+	default:
+		var err error
+		f, err = os.Open(name)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	r := numcsv.NewReader(f)
+	r.Comma = rf.comma
+	r.Comment = rf.comment
+	r.NoHeading = rf.noHeading
+	if rf.nan != "" {
+		r.NaNTokens = strings.Split(rf.nan, ",")
+	}
+	if !r.NoHeading {
+		if _, err := r.ReadHeading(); err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+	}
+	return r, f, nil
+}
+
+func validate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	var rf readFlags
+	rf.register(fs)
+	fs.Parse(args)
+	r, c, err := rf.open(fs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var n, bad int
+	for {
+		record, err := r.Read()
+		var perr *numcsv.ParseError
+		if errors.As(err, &perr) {
+			fmt.Println(perr)
+			bad++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		n++
+	}
+	if bad > 0 {
+		return fmt.Errorf("%d bad lines, %d records read", bad, n)
+	}
+	fmt.Printf("ok: %d records\n", n)
+	return nil
+}
+
+func convert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	var rf readFlags
+	rf.register(fs)
+	outComma := fs.String("outcomma", ",", "field delimiter of the output")
+	format := fs.String("fmt", "g", "format of the values as in strconv.FormatFloat")
+	prec := fs.Int("prec", -1, "precision of the values as in strconv.FormatFloat")
+	cols := fs.String("cols", "", "comma separated headings of the columns to keep")
+	fs.Parse(args)
+	if len(*format) != 1 {
+		return fmt.Errorf("bad format %q", *format)
+	}
+	r, c, err := rf.open(fs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	w := numcsv.NewWriter(os.Stdout)
+	w.Comma = *outComma
+	w.FloatFmt = (*format)[0]
+	w.Precision = *prec
+
+	// The heading is written with the first record, as reading it sets up
+	// the columns, or at the end if there are no records
+	var keep []int
+	heading := func() error {
+		headings := r.DataHeadings()
+		if *cols != "" {
+			var err error
+			keep, err = selectColumns(headings, strings.Split(*cols, ","))
+			if err != nil {
+				return err
+			}
+			selected := make([]string, len(keep))
+			for i, j := range keep {
+				selected[i] = headings[j]
+			}
+			headings = selected
+		}
+		if r.NoHeading {
+			return nil
+		}
+		return w.WriteHeading(headings)
+	}
+	first := true
+	for {
+		record, err := r.Read()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		if first {
+			first = false
+			if err := heading(); err != nil {
+				return err
+			}
+		}
+		if keep != nil {
+			record = pick(record, keep)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	if first {
+		if err := heading(); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func selectColumns(headings, names []string) ([]int, error) {
+	idx := make([]int, len(names))
+	for i, name := range names {
+		idx[i] = -1
+		for j, h := range headings {
+			if h == strings.TrimSpace(name) {
+				idx[i] = j
+				break
+			}
+		}
+		if idx[i] == -1 {
+			return nil, fmt.Errorf("%w: %q", numcsv.ErrUnknownColumn, name)
+		}
+	}
+	return idx, nil
+}
+
+func pick(record []float64, idx []int) []float64 {
+	out := make([]float64, len(idx))
+	for i, j := range idx {
+		out[i] = record[j]
+	}
+	return out
+}
+
+func stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	var rf readFlags
+	rf.register(fs)
+	fs.Parse(args)
+	r, c, err := rf.open(fs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	_, s, err := r.ReadAllStats()
+	if err != nil {
+		return err
+	}
+	headings := r.DataHeadings()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "column\tcount\tmissing\tmin\tmax\tmean\tstd\t")
+	for j := range s.Count {
+		name := fmt.Sprint(j)
+		if j < len(headings) {
+			name = headings[j]
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.6g\t%.6g\t%.6g\t%.6g\t\n",
+			name, s.Count[j], s.Missing[j], s.Min[j], s.Max[j], s.Mean[j], s.Std[j])
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes contents to a file in a temporary directory and returns
+// its path
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// run runs a command with the arguments and returns what it wrote to
+// standard output
+func run(t *testing.T, cmd func([]string) error, args ...string) (string, error) {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stdout := os.Stdout
+	os.Stdout = f
+	err = cmd(args)
+	os.Stdout = stdout
+	if _, serr := f.Seek(0, io.SeekStart); serr != nil {
+		t.Fatal(serr)
+	}
+	b, rerr := io.ReadAll(f)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	return string(b), err
+}
+
+// TestMain runs main instead of the tests when NUMCSV_MAIN is set, so that
+// the exit status can be checked
+func TestMain(m *testing.M) {
+	if args := os.Getenv("NUMCSV_MAIN"); args != "" {
+		os.Args = append([]string{"numcsv"}, strings.Fields(args)...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestValidate(t *testing.T) {
+	out, err := run(t, validate, writeFile(t, "a,b\n1,2\n3,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ok: 2 records\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	bad := writeFile(t, "a,b,c\n1,2,3\n4,x,6\n7,8\n")
+	out, err = run(t, validate, bad)
+	if err == nil || err.Error() != "2 bad lines, 1 records read" {
+		t.Errorf("error = %v, want 2 bad lines", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "line 3, column 1:") || !strings.HasPrefix(lines[1], "line 4:") {
+		t.Errorf("output = %q, want the bad lines 3 and 4", out)
+	}
+
+	for _, test := range []struct {
+		path string
+		code int
+	}{
+		{writeFile(t, "a,b\n1,2\n"), 0},
+		{bad, 1},
+	} {
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		cmd.Env = append(os.Environ(), "NUMCSV_MAIN=validate "+test.path)
+		err := cmd.Run()
+		code := 0
+		var eerr *exec.ExitError
+		if errors.As(err, &eerr) {
+			code = eerr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Errorf("validate %s: exit status = %d, want %d", test.path, code, test.code)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	path := writeFile(t, "a,b,c\n1,2,3\n4.5,5,6\n")
+	for _, test := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "a,b,c\n1,2,3\n4.5,5,6\n"},
+		{[]string{"-outcomma", ";", "-fmt", "f", "-prec", "1"}, "a;b;c\n1.0;2.0;3.0\n4.5;5.0;6.0\n"},
+		{[]string{"-cols", "c,a"}, "c,a\n3,1\n6,4.5\n"},
+		{[]string{"-cols", " b "}, "b\n2\n5\n"},
+		{[]string{"-cols", "a,a"}, "a,a\n1,1\n4.5,4.5\n"},
+	} {
+		out, err := run(t, convert, append(test.args, path)...)
+		if err != nil {
+			t.Errorf("convert %q: unexpected error: %v", test.args, err)
+			continue
+		}
+		if out != test.want {
+			t.Errorf("convert %q: output = %q, want %q", test.args, out, test.want)
+		}
+	}
+
+	// Only the heading is written when there are no records
+	out, err := run(t, convert, "-cols", "b", writeFile(t, "a,b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b\n"; out != want {
+		t.Errorf("no records: output = %q, want %q", out, want)
+	}
+
+	if _, err := run(t, convert, "-cols", "z", path); err == nil || !strings.Contains(err.Error(), `"z"`) {
+		t.Errorf("unknown column: error = %v", err)
+	}
+	if _, err := run(t, convert, "-fmt", "ef", path); err == nil {
+		t.Errorf("no error for a bad format")
+	}
+}
+
+func TestStats(t *testing.T) {
+	out, err := run(t, stats, "-nan", "NA", writeFile(t, "a,b\n1,NA\n3,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"column  count  missing  min  max  mean      std",
+		"a      2        0    1    3     2  1.41421",
+		"b      1        1    4    4     4        0",
+	}
+	checkTable(t, "stats", out, want)
+
+	// A file with only a heading has a row for each column with no values
+	out, err = run(t, stats, writeFile(t, "a,b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"column  count  missing   min   max  mean  std",
+		"a      0        0  +Inf  -Inf   NaN  NaN",
+		"b      0        0  +Inf  -Inf   NaN  NaN",
+	}
+	checkTable(t, "heading only", out, want)
+
+	// An empty file has no columns
+	out, err = run(t, stats, writeFile(t, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTable(t, "empty", out, []string{"column  count  missing  min  max  mean  std"})
+}
+
+// checkTable compares the output of a tabwriter to the wanted lines, ignoring
+// the alignment
+func checkTable(t *testing.T, name, out string, want []string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Errorf("%s: output = %q, want %d lines", name, out, len(want))
+		return
+	}
+	for i, line := range lines {
+		if got, w := strings.Fields(line), strings.Fields(want[i]); strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("%s: line %d = %q, want %q", name, i, line, want[i])
+		}
+	}
+}
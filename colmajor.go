@@ -0,0 +1,59 @@
+package numcsv
+
+// ReadAllColMajor reads all of the records as in ReadAllSlice, but returns
+// the columns of the records instead of the rows, as ReadAllSlice does when
+// Transpose is set. Unless the records must be imputed (except by
+// ImputeDrop), normalized, expanded into one-hot columns, reordered by
+// ColumnOrder, or de-duplicated, the columns are filled as the records are
+// read, without keeping the rows. If a record cannot be read, the returned
+// error is a *PartialError holding the columns read before it.
+func (r *Reader) ReadAllColMajor() ([][]float64, error) {
+	if !r.streamColumns() {
+		r.Transpose = !r.Transpose
+		defer func() { r.Transpose = !r.Transpose }()
+		return r.ReadAllSlice()
+	}
+	var cols [][]float64
+	var err error
+	for n := 0; r.MaxRows <= 0 || n < r.MaxRows; {
+		var data []float64
+		data, err = r.Read()
+		if err != nil || data == nil {
+			break
+		}
+		r.progress(false)
+		if r.RowFilter != nil && !r.RowFilter(data) {
+			continue
+		}
+		if r.Impute == ImputeDrop && hasNaN(data) {
+			continue
+		}
+		if cols == nil {
+			cols = make([][]float64, len(data))
+		}
+		for j, v := range data {
+			cols[j] = append(cols[j], v)
+		}
+		n++
+	}
+	r.progress(true)
+	if err != nil {
+		return nil, &PartialError{Data: cols, Line: r.line, Err: err}
+	}
+	return cols, nil
+}
+
+// streamColumns returns whether the columns can be filled as the records
+// are read
+func (r *Reader) streamColumns() bool {
+	if (r.Impute != ImputeNone && r.Impute != ImputeDrop) || r.Normalize != NormalizeNone ||
+		r.ColumnOrder != nil || r.Transpose || r.Dedupe != DedupeNone {
+		return false
+	}
+	for _, c := range r.CategoricalColumns {
+		if c.OneHot {
+			return false
+		}
+	}
+	return true
+}
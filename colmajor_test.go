@@ -0,0 +1,61 @@
+package numcsv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAllColMajor(t *testing.T) {
+	const input = "a,b\n1,2\n3,NA\n5,6\n"
+	want := [][]float64{{1, 5}, {2, 6}}
+	for _, test := range []struct {
+		name  string
+		setup func(r *Reader)
+	}{
+		// Filled as the records are read
+		{"stream", func(r *Reader) {}},
+		// Read with ReadAllSlice and Transpose
+		{"transpose", func(r *Reader) { r.ColumnOrder = []string{"a", "b"} }},
+	} {
+		r := NewReader(strings.NewReader(input))
+		r.NaNTokens = []string{"NA"}
+		r.Impute = ImputeDrop
+		test.setup(r)
+		r.ReadHeading()
+		if got := r.streamColumns(); got != (test.name == "stream") {
+			t.Errorf("%s: streamColumns = %v", test.name, got)
+		}
+		cols, err := r.ReadAllColMajor()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(cols, want) {
+			t.Errorf("%s: columns = %v, want %v", test.name, cols, want)
+		}
+		if r.Transpose {
+			t.Errorf("%s: Transpose left set", test.name)
+		}
+	}
+
+	// A set Transpose gives the rows
+	r := NewReader(strings.NewReader("a,b\n1,2\n3,4\n"))
+	r.Transpose = true
+	r.ReadHeading()
+	rows, err := r.ReadAllColMajor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(rows, want) || !r.Transpose {
+		t.Errorf("Transpose: got %v, want %v", rows, want)
+	}
+
+	r = NewReader(strings.NewReader("a,b\n1,2\n3\n"))
+	r.ReadHeading()
+	_, err = r.ReadAllColMajor()
+	var perr *PartialError
+	if !errors.As(err, &perr) || !reflect.DeepEqual(perr.Data, [][]float64{{1}, {2}}) {
+		t.Errorf("err = %v, want a PartialError with the first record", err)
+	}
+}
@@ -0,0 +1,460 @@
+package numcsv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultBoolTokens are the tokens recognized in boolean columns when
+// Reader.BoolTokens is nil.
+var DefaultBoolTokens = map[string]bool{
+	"true":  true,
+	"false": false,
+	"yes":   true,
+	"no":    false,
+	"y":     true,
+	"n":     false,
+	"1":     true,
+	"0":     false,
+}
+
+// Column identifies a column either by its heading or by its index
+type Column struct {
+	Name  string // heading of the column. If "", Index is used instead
+	Index int    // index of the column in the record
+}
+
+type colKind int
+
+const (
+	floatKind colKind = iota
+	timeKind
+	intKind
+	stringKind
+	boolKind
+	catKind
+	uncertainKind
+	fractionKind
+	skipKind // omitted from the records
+)
+
+// column is the parsing state of a single column of the file
+type column struct {
+	kind  colKind
+	time  *timeColumn
+	bools map[string]bool
+	cat   *catColumn
+}
+
+// setup resolves the column declarations against the headings. It is called
+// once the number of fields is known, before the first record is parsed.
+func (r *Reader) setup(first []string) error {
+	r.cols = make([]column, r.FieldsPerRecord)
+	r.times = r.times[:0]
+	for k, c := range r.TimeColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		tc := &timeColumn{TimeColumn: c}
+		if k < len(r.prevTimes) && r.prevTimes[k].n > 0 {
+			prev := r.prevTimes[k]
+			tc.chained = true
+			tc.next = prev.last + prev.step
+		}
+		r.cols[j] = column{kind: timeKind, time: tc}
+		r.times = append(r.times, tc)
+	}
+	for _, c := range r.IntColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		r.cols[j] = column{kind: intKind}
+	}
+	if len(r.BoolColumns) != 0 {
+		tokens := r.BoolTokens
+		if tokens == nil {
+			tokens = DefaultBoolTokens
+		}
+		bools := make(map[string]bool, len(tokens))
+		for tok, v := range tokens {
+			bools[strings.ToLower(tok)] = v
+		}
+		for _, c := range r.BoolColumns {
+			j, err := r.columnIndex(c.Name, c.Index)
+			if err != nil {
+				return err
+			}
+			r.cols[j] = column{kind: boolKind, bools: bools}
+		}
+	}
+	r.cats = r.cats[:0]
+	for _, c := range r.CategoricalColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		cat := newCatColumn(c, j)
+		r.cols[j] = column{kind: catKind, cat: cat}
+		r.cats = append(r.cats, cat)
+	}
+	for _, c := range r.UncertainColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		r.cols[j] = column{kind: uncertainKind}
+	}
+	for _, c := range r.FractionColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		r.cols[j] = column{kind: fractionKind}
+	}
+	for _, c := range r.StringColumns {
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err != nil {
+			return err
+		}
+		r.cols[j] = column{kind: stringKind}
+	}
+	if r.DetectStrings {
+		r.detectStrings(first)
+	}
+	if r.DuplicateHeadings == DuplicateKeepFirst {
+		seen := make(map[string]bool, len(r.headings))
+		for j, h := range r.headings {
+			if seen[h] {
+				r.cols[j] = column{kind: skipKind}
+			}
+			seen[h] = true
+		}
+	}
+
+	r.width = 0
+	r.dataHeadings = nil
+	if r.AddIndex {
+		r.width++
+		if r.headings != nil {
+			name := r.IndexHeading
+			if name == "" {
+				name = "index"
+			}
+			r.dataHeadings = append(r.dataHeadings, name)
+		}
+	}
+	r.outIndex = make([]int, r.FieldsPerRecord)
+	for j, c := range r.cols {
+		r.outIndex[j] = -1
+		if c.kind == stringKind || c.kind == skipKind {
+			continue
+		}
+		r.outIndex[j] = r.width
+		r.width++
+		if r.headings != nil {
+			r.dataHeadings = append(r.dataHeadings, r.headings[j])
+		}
+		if c.kind == uncertainKind {
+			r.width++
+			if r.headings != nil {
+				r.dataHeadings = append(r.dataHeadings, r.headings[j]+"_sigma")
+			}
+		}
+	}
+	r.order = nil
+	if r.ColumnOrder != nil {
+		r.order = make([]int, len(r.ColumnOrder))
+		for i, name := range r.ColumnOrder {
+			r.order[i] = -1
+			for j, h := range r.dataHeadings {
+				if h == name {
+					r.order[i] = j
+					break
+				}
+			}
+			if r.order[i] < 0 {
+				return fmt.Errorf("%w: %q", ErrUnknownColumn, name)
+			}
+		}
+	}
+	r.checks = r.checks[:0]
+	for _, c := range r.Checks {
+		chk := check{Check: c}
+		for _, col := range c.Columns {
+			j, err := r.columnIndex(col.Name, col.Index)
+			if err != nil {
+				return err
+			}
+			if r.outIndex[j] < 0 {
+				return fmt.Errorf("check %q uses string column %d", c.Name, j)
+			}
+			chk.idx = append(chk.idx, r.outIndex[j])
+		}
+		chk.vals = make([]float64, len(chk.idx))
+		r.checks = append(r.checks, chk)
+	}
+
+	r.ints = make([]int64, r.FieldsPerRecord)
+	r.strs = make([]string, r.FieldsPerRecord)
+	r.isSetup = true
+	return nil
+}
+
+// detectStrings sets the columns whose first value that is not missing is
+// not a number to be string columns. If a value of first is missing, the
+// following lines are read ahead, and kept to be returned by next, until
+// each column has a value.
+func (r *Reader) detectStrings(first []string) {
+	decided := make([]bool, len(r.cols))
+	n := 0
+	for j, c := range r.cols {
+		if c.kind != floatKind {
+			decided[j] = true
+			n++
+		}
+	}
+	decide := func(strs []string) {
+		for j, str := range strs {
+			if decided[j] || r.isNaN(str) {
+				continue
+			}
+			decided[j] = true
+			n++
+			if _, err := parseFloat(str); err != nil {
+				r.cols[j].kind = stringKind
+			}
+		}
+	}
+	decide(first)
+	if n == len(decided) {
+		return
+	}
+	// first is still being parsed, so the lines read ahead must not reuse it
+	r.fieldBuf = nil
+	line, file := r.line, r.lineFile
+	var pending []pendingLine
+	for n < len(decided) {
+		strs, ok := r.next()
+		if !ok {
+			break
+		}
+		strs = append([]string(nil), strs...)
+		pending = append(pending, pendingLine{fields: strs, line: r.line, file: r.lineFile})
+		if len(strs) == len(decided) {
+			decide(strs)
+		}
+	}
+	r.pending = pending
+	r.line, r.lineFile = line, file
+}
+
+// pendingLine is a line read ahead of the record being parsed
+type pendingLine struct {
+	fields []string
+	line   int
+	file   int
+}
+
+// outColumns returns the indices in the record of the columns of the records
+// returned by ReadAllSlice, before one-hot expansion
+func (r *Reader) outColumns() []int {
+	if r.order != nil {
+		return r.order
+	}
+	n := r.FieldsPerRecord
+	if r.isSetup {
+		n = r.width
+	}
+	cols := make([]int, n)
+	for j := range cols {
+		cols[j] = j
+	}
+	return cols
+}
+
+// columnIndex returns the index of the column with the given heading. If name
+// is "", index is returned after checking it is in range.
+func (r *Reader) columnIndex(name string, index int) (int, error) {
+	if name == "" {
+		if index < 0 || index >= r.FieldsPerRecord {
+			return -1, fmt.Errorf("%w: index %d", ErrUnknownColumn, index)
+		}
+		return index, nil
+	}
+	for i, h := range r.headings {
+		if h == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %q", ErrUnknownColumn, name)
+}
+
+// parseField converts the string in column j to a float
+func (r *Reader) parseField(j int, str string) (float64, error) {
+	if r.isNaN(str) {
+		if r.cols[j].kind == intKind {
+			r.ints[j] = 0
+		}
+		return math.NaN(), nil
+	}
+	switch c := r.cols[j]; c.kind {
+	case timeKind:
+		return c.time.parse(str)
+	case intKind:
+		v, err := parseInt(str)
+		r.ints[j] = v
+		return float64(v), err
+	case boolKind:
+		v, ok := c.bools[strings.ToLower(str)]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownToken, str)
+		}
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case catKind:
+		return c.cat.parse(str)
+	case fractionKind:
+		return parseFraction(str)
+	}
+	return parseFloat(str)
+}
+
+// isNaN returns whether str is one of the NaNTokens
+func (r *Reader) isNaN(str string) bool {
+	for _, tok := range r.NaNTokens {
+		if str == tok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUncertain parses a value with an uncertainty
+func (r *Reader) parseUncertain(str string) (v, sigma float64, err error) {
+	if r.isNaN(str) {
+		return math.NaN(), math.NaN(), nil
+	}
+	sigma = math.NaN()
+	for _, sep := range []string{"±", "+/-", "+-"} {
+		if i := strings.Index(str, sep); i > 0 {
+			sigma, err = strconv.ParseFloat(strings.TrimSpace(str[i+len(sep):]), 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			str = strings.TrimSpace(str[:i])
+			break
+		}
+	}
+	v, err = parseFloat(str)
+	return v, sigma, err
+}
+
+// parseFloat parses a float as strconv.ParseFloat, also accepting the
+// spellings of infinity and NaN written by Excel and C runtimes, such as
+// "1.#INF", "-1.#IND", "1.#QNAN", "-nan(ind)" and "∞"
+func parseFloat(str string) (float64, error) {
+	if v, ok := fastFloat(str); ok {
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err == nil {
+		return v, nil
+	}
+	if s, ok := specialFloat(str); ok {
+		return s, nil
+	}
+	return v, err
+}
+
+func specialFloat(str string) (float64, bool) {
+	s := strings.ToLower(str)
+	sign := 1
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	s = strings.TrimPrefix(s, "1.")
+	// Excel and MSVC pad the token with zeros, as in "1.#INF00"
+	s = strings.TrimRight(s, "0")
+	switch {
+	case s == "#inf" || s == "∞" || s == "infinite":
+		return math.Inf(sign), true
+	case s == "#ind" || s == "#qnan" || s == "#snan" || s == "#nan",
+		strings.HasPrefix(s, "nan(") && strings.HasSuffix(s, ")"):
+		return math.NaN(), true
+	}
+	return 0, false
+}
+
+// parseFraction parses a fraction such as "3/4" or "1 1/2", or a ratio such
+// as "1:250", as well as plain numbers
+func parseFraction(str string) (float64, error) {
+	i := strings.IndexAny(str, "/:")
+	if i < 0 {
+		return parseFloat(str)
+	}
+	num, den := strings.TrimSpace(str[:i]), strings.TrimSpace(str[i+1:])
+	var whole float64
+	if k := strings.LastIndexByte(num, ' '); k >= 0 && str[i] == '/' {
+		w, err := strconv.ParseFloat(num[:k], 64)
+		if err != nil {
+			return 0, err
+		}
+		whole, num = w, strings.TrimSpace(num[k+1:])
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, err
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil {
+		return 0, err
+	}
+	if d == 0 {
+		return 0, fmt.Errorf("zero denominator in %q", str)
+	}
+	if math.Signbit(whole) {
+		// The fraction of a negative mixed number is also negative
+		return whole - n/d, nil
+	}
+	return whole + n/d, nil
+}
+
+// parseInt parses a decimal integer, or a hexadecimal, binary or octal
+// integer with a 0x, 0b or 0o prefix. Unlike strconv.ParseInt with base 0, a
+// leading zero does not make the integer octal, and underscores are not
+// allowed between the digits.
+func parseInt(str string) (int64, error) {
+	sign, digits := "", str
+	if len(str) > 0 && (str[0] == '+' || str[0] == '-') {
+		sign, digits = str[:1], str[1:]
+	}
+	if len(digits) > 2 && digits[0] == '0' {
+		base := 0
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 0 {
+			v, err := strconv.ParseInt(sign+digits[2:], base, 64)
+			if nerr, ok := err.(*strconv.NumError); ok {
+				nerr.Num = str
+			}
+			return v, err
+		}
+	}
+	return strconv.ParseInt(str, 10, 64)
+}
@@ -0,0 +1,189 @@
+package numcsv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// String columns are detected from the first value that is not missing
+func TestDetectStringsMissing(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\nNA,NA\n\n1,x\n2,y\n"))
+	r.NaNTokens = []string{"NA"}
+	r.DetectStrings = true
+	r.EmptyRows = EmptyRowSkip
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	tab, err := r.ReadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(tab.Headings, want) {
+		t.Errorf("Headings = %q, want %q", tab.Headings, want)
+	}
+	if len(tab.Data) != 3 || !math.IsNaN(tab.Data[0][0]) || tab.Data[1][0] != 1 || tab.Data[2][0] != 2 {
+		t.Errorf("Data = %v, want [[NaN] [1] [2]]", tab.Data)
+	}
+	if want := []string{"NA", "x", "y"}; !reflect.DeepEqual(tab.Strings("b"), want) {
+		t.Errorf("Strings = %q, want %q", tab.Strings("b"), want)
+	}
+
+	// The lines read ahead keep their line numbers
+	r = NewReader(strings.NewReader("a,b\nNA,1\n2,3\nx,4\n"))
+	r.NaNTokens = []string{"NA"}
+	r.DetectStrings = true
+	r.ReadHeading()
+	_, err = r.ReadAllSlice()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 4 {
+		t.Errorf("err = %v, want a ParseError on line 4", err)
+	}
+}
+
+func TestParseFloatSpecial(t *testing.T) {
+	inf := math.Inf(1)
+	for str, want := range map[string]float64{
+		"1.#INF":    inf,
+		"-1.#INF":   -inf,
+		"+1.#INF":   inf,
+		"1.#INF00":  inf,
+		"-1.#INF00": -inf,
+		"#INF":      inf,
+		"1.#inf":    inf,
+		"∞":         inf,
+		"-∞":        -inf,
+		"Infinite":  inf,
+		"-infinite": -inf,
+		"Inf":       inf,
+		"-Infinity": -inf,
+	} {
+		got, err := parseFloat(str)
+		if err != nil || got != want {
+			t.Errorf("parseFloat(%q) = %v, %v, want %v", str, got, err, want)
+		}
+	}
+	for _, str := range []string{
+		"1.#IND", "-1.#IND", "1.#IND00", "1.#QNAN", "-1.#QNAN0", "1.#SNAN",
+		"#NAN", "nan(ind)", "-nan(ind)", "NaN(snan)", "nan",
+	} {
+		got, err := parseFloat(str)
+		if err != nil || !math.IsNaN(got) {
+			t.Errorf("parseFloat(%q) = %v, %v, want NaN", str, got, err)
+		}
+	}
+	for _, str := range []string{"1.#X", "2.#INF", "1.5#INF", "#INF#", "nan(", "nanind)", "infx", "--1.#INF", ""} {
+		if v, err := parseFloat(str); err == nil {
+			t.Errorf("parseFloat(%q) = %v, want error", str, v)
+		}
+	}
+
+	r := NewReader(strings.NewReader("a,b\n1.#INF,-1.#IND\n-1.#INF00,nan(ind)\n"))
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{inf, math.NaN()}, {-inf, math.NaN()}}; !equalBits(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestParseFraction(t *testing.T) {
+	for str, want := range map[string]float64{
+		"3/4":     0.75,
+		"1 1/2":   1.5,
+		"-1 1/2":  -1.5,
+		"-3/4":    -0.75,
+		"1:250":   0.004,
+		" 2 / 8 ": 0.25,
+		"0.5":     0.5,
+		"0/3":     0,
+	} {
+		got, err := parseFraction(str)
+		if err != nil || got != want {
+			t.Errorf("parseFraction(%q) = %v, %v, want %v", str, got, err, want)
+		}
+	}
+	for _, str := range []string{"1/0", "1:0", "1 1/0", "x/2", "1/y", "a 1/2"} {
+		if v, err := parseFraction(str); err == nil {
+			t.Errorf("parseFraction(%q) = %v, want error", str, v)
+		}
+	}
+
+	r := NewReader(strings.NewReader("f,v\n1/4,1\n2:1,2\n"))
+	r.FractionColumns = []Column{{Name: "f"}}
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{0.25, 1}, {2, 2}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	for str, want := range map[string]int64{
+		"42":                  42,
+		"-7":                  -7,
+		"+7":                  7,
+		"010":                 10,
+		"0x1F":                31,
+		"0X1f":                31,
+		"-0x10":               -16,
+		"0b101":               5,
+		"0B11":                3,
+		"0o17":                15,
+		"-0x8000000000000000": math.MinInt64,
+	} {
+		got, err := parseInt(str)
+		if err != nil || got != want {
+			t.Errorf("parseInt(%q) = %v, %v, want %v", str, got, err, want)
+		}
+	}
+	// Underscores are rejected in every base
+	for _, str := range []string{"0x1_F", "1_000", "0b1_0", "0x", "0xG", "0b2", "0o8", "--1", "0x8000000000000000", "1.5"} {
+		if v, err := parseInt(str); err == nil {
+			t.Errorf("parseInt(%q) = %v, want error", str, v)
+		}
+	}
+	_, err := parseInt("0xG")
+	if !strings.Contains(err.Error(), `"0xG"`) {
+		t.Errorf("error %q does not quote the input", err)
+	}
+}
+
+func TestUncertainColumns(t *testing.T) {
+	r := NewReader(strings.NewReader("x,v (F)\n1,1.5±0.25\n2,2 +/- 0.5\n3,-3+-1\n4,4\n5,NA\n"))
+	r.NaNTokens = []string{"NA"}
+	r.ParseUnits = true
+	r.StripUnits = true
+	r.UncertainColumns = []Column{{Name: "v"}}
+	r.UnitConversions = map[string]UnitConversion{"F": {To: "K", Scale: -2, Offset: 10}}
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "v", "v_sigma"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	// The value is scaled and offset, and the uncertainty only scaled by the
+	// magnitude of the scale
+	nan := math.NaN()
+	want := [][]float64{{1, 7, 0.5}, {2, 6, 1}, {3, 16, 2}, {4, 2, nan}, {5, nan, nan}}
+	if !equalBits(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+
+	for _, input := range []string{"v\n1±x\n", "v\nx±1\n"} {
+		r := NewReader(strings.NewReader(input))
+		r.UncertainColumns = []Column{{Name: "v"}}
+		r.ReadHeading()
+		if _, err := r.ReadAllSlice(); err == nil {
+			t.Errorf("%q: no error", input)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package numcsv
+
+import "math"
+
+// Converter converts the values of a record after they are parsed. col is the
+// index of the column in the file. The uncertainty of UncertainColumns, the
+// index column, and the values of BoolColumns and CategoricalColumns, which
+// are tokens rather than measurements, are not converted.
+type Converter interface {
+	Convert(col int, v float64) float64
+}
+
+// ConverterFunc is a function used as a Converter
+type ConverterFunc func(col int, v float64) float64
+
+func (f ConverterFunc) Convert(col int, v float64) float64 {
+	return f(col, v)
+}
+
+// Linear converts the values of column Col to v*Scale + Offset, for example
+// to change units. If Col is negative, every column is converted.
+type Linear struct {
+	Col    int
+	Scale  float64
+	Offset float64
+}
+
+func (l Linear) Convert(col int, v float64) float64 {
+	if l.Col >= 0 && col != l.Col {
+		return v
+	}
+	return v*l.Scale + l.Offset
+}
+
+// Clamp limits the values of column Col to [Min, Max]. If Col is negative,
+// every column is clamped.
+type Clamp struct {
+	Col      int
+	Min, Max float64
+}
+
+func (c Clamp) Convert(col int, v float64) float64 {
+	if c.Col >= 0 && col != c.Col {
+		return v
+	}
+	return math.Max(c.Min, math.Min(c.Max, v))
+}
+
+// Deadband sets the values of column Col with magnitude less than Width to
+// zero. If Col is negative, every column is filtered.
+type Deadband struct {
+	Col   int
+	Width float64
+}
+
+func (d Deadband) Convert(col int, v float64) float64 {
+	if d.Col >= 0 && col != d.Col {
+		return v
+	}
+	if math.Abs(v) < d.Width {
+		return 0
+	}
+	return v
+}
+
+func (r *Reader) convert(col int, v float64) float64 {
+	if k := r.cols[col].kind; k == boolKind || k == catKind {
+		return v
+	}
+	v = r.convertUnit(col, v, false)
+	for _, c := range r.Converters {
+		v = c.Convert(col, v)
+	}
+	if r.Transform != nil {
+		v = r.Transform(col, v)
+	}
+	return v
+}
@@ -0,0 +1,26 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Converters and Transform apply to numbers, not to boolean or categorical tokens
+func TestConvertSkipsTokens(t *testing.T) {
+	r := NewReader(strings.NewReader("a,c,b\n1,low,yes\n2,high,no\n"))
+	r.Converters = []Converter{Linear{Col: -1, Scale: 10}}
+	r.Transform = func(_ int, v float64) float64 { return v + 1 }
+	r.CategoricalColumns = []CategoricalColumn{{Name: "c", OneHot: true}}
+	r.BoolColumns = []Column{{Name: "b"}}
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{11, 1, 0, 1}, {21, 0, 1, 0}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
@@ -0,0 +1,125 @@
+package numcsv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Decode reads the rest of the records into dst, which must be a pointer to
+// a slice of structs. Each record is appended to the slice as a struct, with
+// the fields tagged `numcsv:"heading"` set from the column with that heading.
+// Fields may be floats, integers, bools (set if the value is not zero), or
+// strings for string columns. Integer fields of IntColumns are set exactly.
+// The values are those returned by Read, and ReadHeading must be called first.
+// Tagged fields must be exported. A value that is NaN, or does not fit in an
+// integer field, is an error.
+func (r *Reader) Decode(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice || v.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("decode destination must be a pointer to a slice of structs")
+	}
+	slice := v.Elem()
+	typ := slice.Type().Elem()
+	var fields []decodeField
+	for {
+		data, err := r.Read()
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			return nil
+		}
+		if fields == nil {
+			fields, err = r.decodeFields(typ)
+			if err != nil {
+				return err
+			}
+		}
+		elem := reflect.New(typ).Elem()
+		for _, f := range fields {
+			if err := r.decodeField(elem.Field(f.field), f.col, data); err != nil {
+				return fmt.Errorf("%w: field %s", err, typ.Field(f.field).Name)
+			}
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+}
+
+// decodeField is a struct field set from a column of the file
+type decodeField struct {
+	field int // index of the struct field
+	col   int // index of the column in the file
+}
+
+func (r *Reader) decodeFields(typ reflect.Type) ([]decodeField, error) {
+	fields := []decodeField{}
+	for i := 0; i < typ.NumField(); i++ {
+		name := typ.Field(i).Tag.Get("numcsv")
+		if name == "" || name == "-" {
+			continue
+		}
+		if !typ.Field(i).IsExported() {
+			return nil, fmt.Errorf("cannot decode into unexported field %s", typ.Field(i).Name)
+		}
+		j, err := r.columnIndex(name, 0)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, decodeField{field: i, col: j})
+	}
+	return fields, nil
+}
+
+func (r *Reader) decodeField(f reflect.Value, j int, data []float64) error {
+	if r.cols[j].kind == stringKind {
+		if f.Kind() != reflect.String {
+			return fmt.Errorf("cannot decode string column %q", r.headings[j])
+		}
+		f.SetString(r.strs[j])
+		return nil
+	}
+	v := data[r.outIndex[j]]
+	switch f.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f.SetFloat(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch {
+		case math.IsNaN(v) || v < math.MinInt64 || v >= math.MaxInt64:
+			return fmt.Errorf("cannot decode %v into %s", v, f.Type())
+		case r.cols[j].kind == intKind:
+			n = r.ints[j]
+		case v != math.Trunc(v):
+			return fmt.Errorf("cannot decode %v into %s", v, f.Type())
+		default:
+			n = int64(v)
+		}
+		if f.OverflowInt(n) {
+			return fmt.Errorf("cannot decode %v into %s", n, f.Type())
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		switch {
+		case math.IsNaN(v) || v < 0 || v >= math.MaxUint64:
+			return fmt.Errorf("cannot decode %v into %s", v, f.Type())
+		case r.cols[j].kind == intKind:
+			n = uint64(r.ints[j])
+		case v != math.Trunc(v):
+			return fmt.Errorf("cannot decode %v into %s", v, f.Type())
+		default:
+			n = uint64(v)
+		}
+		if f.OverflowUint(n) {
+			return fmt.Errorf("cannot decode %v into %s", n, f.Type())
+		}
+		f.SetUint(n)
+	case reflect.Bool:
+		f.SetBool(v != 0)
+	default:
+		return fmt.Errorf("cannot decode column %q into %s", r.headings[j], f.Type())
+	}
+	return nil
+}
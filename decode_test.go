@@ -0,0 +1,57 @@
+package numcsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	type record struct {
+		A float64 `numcsv:"a"`
+		B int     `numcsv:"b"`
+		C uint8   `numcsv:"c"`
+		S string  `numcsv:"s"`
+		X float64 `numcsv:"-"`
+	}
+	r := NewReader(strings.NewReader("a,b,c,s\n1.5,-4,200,x\n2,9007199254740993,0,y\n"))
+	r.IntColumns = []Column{{Name: "b"}}
+	r.StringColumns = []Column{{Name: "s"}}
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	var got []record
+	if err := r.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{{1.5, -4, 200, "x", 0}, {2, 9007199254740993, 0, "y", 0}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Decode = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type unexported struct {
+		A float64 `numcsv:"a"`
+		b float64 `numcsv:"b"`
+	}
+	r := NewReader(strings.NewReader("a,b\n1,2\n"))
+	r.ReadHeading()
+	var u []unexported
+	if err := r.Decode(&u); err == nil {
+		t.Errorf("unexported field: no error")
+	}
+
+	type ints struct {
+		A uint8 `numcsv:"a"`
+		B int   `numcsv:"b"`
+	}
+	for _, input := range []string{"a,b\n-1,2\n", "a,b\n300,2\n", "a,b\n1,NA\n", "a,b\n1,1e300\n", "a,b\n1,2.7\n", "a,b\n1.5,2\n"} {
+		r := NewReader(strings.NewReader(input))
+		r.NaNTokens = []string{"NA"}
+		r.ReadHeading()
+		var got []ints
+		if err := r.Decode(&got); err == nil {
+			t.Errorf("%q: no error, decoded %v", input, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package numcsv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Dedupe sets how ReadAllSlice and ReadTable handle duplicate records, such
+// as those re-emitted by a logger after it reconnects
+type Dedupe int
+
+const (
+	DedupeNone  Dedupe = iota // keep all records
+	DedupeFirst               // keep only the first of the duplicate records
+	DedupeLast                // keep the values of the last duplicate, at the position of the first
+)
+
+// deduper finds the records that duplicate an earlier record
+type deduper struct {
+	key  int            // index of the key column in the record, or -1 for the whole record
+	skip int            // number of values at the start of the record not in the whole record key
+	seen map[string]int // index of the first record with each key
+	buf  []byte
+}
+
+func (r *Reader) newDeduper() (*deduper, error) {
+	d := &deduper{key: -1, seen: make(map[string]int)}
+	if r.AddIndex {
+		// The index differs between every record
+		d.skip = 1
+	}
+	if r.DedupeKey != "" {
+		d.key = indexOf(r.dataHeadings, r.DedupeKey)
+		if d.key < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownColumn, r.DedupeKey)
+		}
+	}
+	return d, nil
+}
+
+// find returns the index of the earlier record with the same key as data,
+// and whether there is one. If not, data is recorded as having index i.
+func (d *deduper) find(data []float64, i int) (int, bool) {
+	vals := data[d.skip:]
+	if d.key >= 0 {
+		vals = data[d.key : d.key+1]
+	}
+	d.buf = d.buf[:0]
+	for _, v := range vals {
+		d.buf = binary.LittleEndian.AppendUint64(d.buf, math.Float64bits(v))
+	}
+	if j, ok := d.seen[string(d.buf)]; ok {
+		return j, true
+	}
+	d.seen[string(d.buf)] = i
+	return i, false
+}
@@ -0,0 +1,62 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupeStats(t *testing.T) {
+	const input = "k,v\n1,10\n2,NA\n1,30\n"
+	for _, test := range []struct {
+		dedupe Dedupe
+		data   [][]float64
+		mean   float64
+	}{
+		{DedupeNone, [][]float64{{1, 10}, {2, 20}, {1, 30}}, 20},
+		{DedupeFirst, [][]float64{{1, 10}, {2, 10}}, 10},
+		{DedupeLast, [][]float64{{1, 30}, {2, 30}}, 30},
+	} {
+		r := NewReader(strings.NewReader(input))
+		r.NaNTokens = []string{"NA"}
+		r.Dedupe = test.dedupe
+		r.DedupeKey = "k"
+		r.Impute = ImputeMean
+		if _, err := r.ReadHeading(); err != nil {
+			t.Fatal(err)
+		}
+		data, stats, err := r.ReadAllStats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(data, test.data) {
+			t.Errorf("dedupe %d: data = %v, want %v", test.dedupe, data, test.data)
+		}
+		if stats.Mean[1] != test.mean || stats.Missing[1] != 1 {
+			t.Errorf("dedupe %d: mean = %v, missing = %d, want %v and 1", test.dedupe, stats.Mean[1], stats.Missing[1], test.mean)
+		}
+		if test.dedupe == DedupeLast && stats.Count[1] != 1 {
+			t.Errorf("dedupe %d: count = %d, want 1", test.dedupe, stats.Count[1])
+		}
+		if math.IsNaN(stats.Std[0]) {
+			t.Errorf("dedupe %d: std of the key is NaN", test.dedupe)
+		}
+	}
+}
+
+func TestDedupeAddIndex(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n1,2\n1,2\n3,4\n"))
+	r.AddIndex = true
+	r.Dedupe = DedupeFirst
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{0, 1, 2}, {2, 3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
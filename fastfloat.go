@@ -0,0 +1,96 @@
+package numcsv
+
+import "math"
+
+// pow10 holds the powers of ten that are exactly representable as float64s
+var pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
+}
+
+// fastFloat parses the common case of a decimal number such as "-12.5e3"
+// without allocating. It handles numbers whose significant digits fit in 53
+// bits and whose decimal exponent is at most 22 in magnitude, for which a
+// single multiplication or division is correctly rounded (Clinger's fast
+// path). ok is false for any other input, which must be parsed by
+// strconv.ParseFloat.
+func fastFloat(s string) (v float64, ok bool) {
+	i := 0
+	neg := false
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		neg = s[i] == '-'
+		i++
+	}
+	var mant uint64
+	digits, exp := 0, 0
+	sawDigit, sawDot := false, false
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			sawDigit = true
+			if mant == 0 && c == '0' {
+				if sawDot {
+					exp--
+				}
+				continue
+			}
+			digits++
+			if digits > 19 {
+				return 0, false
+			}
+			mant = mant*10 + uint64(c-'0')
+			if sawDot {
+				exp--
+			}
+			continue
+		case c == '.' && !sawDot:
+			sawDot = true
+			continue
+		}
+		break
+	}
+	if !sawDigit {
+		return 0, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		eneg := false
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			eneg = s[i] == '-'
+			i++
+		}
+		if i == len(s) {
+			return 0, false
+		}
+		e := 0
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c < '0' || c > '9' || e > 1000 {
+				return 0, false
+			}
+			e = e*10 + int(c-'0')
+		}
+		if eneg {
+			e = -e
+		}
+		exp += e
+	}
+	if i != len(s) || mant > 1<<53 {
+		return 0, false
+	}
+	v = float64(mant)
+	switch {
+	case mant == 0:
+	case exp < -22 || exp > 22:
+		return 0, false
+	case exp < 0:
+		v /= pow10[-exp]
+	default:
+		v *= pow10[exp]
+	}
+	if neg {
+		v = math.Copysign(v, -1)
+	}
+	return v, true
+}
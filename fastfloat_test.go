@@ -0,0 +1,186 @@
+package numcsv
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkFastFloat checks that fastFloat agrees exactly with strconv.ParseFloat
+// whenever it accepts s
+func checkFastFloat(t *testing.T, s string) bool {
+	t.Helper()
+	v, ok := fastFloat(s)
+	if !ok {
+		return false
+	}
+	want, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Errorf("fastFloat(%q) = %v, but strconv.ParseFloat fails: %v", s, v, err)
+		return true
+	}
+	if math.Float64bits(v) != math.Float64bits(want) {
+		t.Errorf("fastFloat(%q) = %v (%#x), strconv.ParseFloat = %v (%#x)", s, v, math.Float64bits(v), want, math.Float64bits(want))
+	}
+	return true
+}
+
+func TestFastFloat(t *testing.T) {
+	for _, test := range []struct {
+		s  string
+		ok bool // whether fastFloat takes the fast path
+	}{
+		{"0", true},
+		{"-0", true},
+		{"+1", true},
+		{"1.5", true},
+		{".5", true},
+		{"5.", true},
+		{"-12.5e3", true},
+		{"1E-5", true},
+		{"0.000", true},
+		{"00012.50", true},
+		{"1e22", true},
+		{"9007199254740992", true},
+		{"0.1", true},
+		{"1e23", false},
+		{"1e-23", false},
+		{"9007199254740993", false},
+		{"12345678901234567890", false},
+		{"1e", false},
+		{"1e+", false},
+		{"e5", false},
+		{".", false},
+		{"-", false},
+		{"", false},
+		{"1.2.3", false},
+		{"1_000", false},
+		{"0x10", false},
+		{"Inf", false},
+		{"NaN", false},
+		{"1e400", false},
+		{" 1", false},
+		{"1 ", false},
+	} {
+		if ok := checkFastFloat(t, test.s); ok != test.ok {
+			t.Errorf("fastFloat(%q): ok = %v, want %v", test.s, ok, test.ok)
+		}
+	}
+}
+
+// randomDecimal returns a random decimal number, favoring the forms found in
+// CSV files but including many which are not on the fast path
+func randomDecimal(rnd *rand.Rand) string {
+	var b strings.Builder
+	switch rnd.Intn(4) {
+	case 0:
+		b.WriteByte('-')
+	case 1:
+		b.WriteByte('+')
+	}
+	digits := func(n int) {
+		for i := 0; i < n; i++ {
+			b.WriteByte(byte('0' + rnd.Intn(10)))
+		}
+	}
+	for i := rnd.Intn(3); i > 0 && rnd.Intn(2) == 0; i-- {
+		b.WriteByte('0')
+	}
+	digits(rnd.Intn(12))
+	if rnd.Intn(4) != 0 {
+		b.WriteByte('.')
+		digits(rnd.Intn(12))
+	}
+	if rnd.Intn(3) == 0 {
+		b.WriteByte("eE"[rnd.Intn(2)])
+		switch rnd.Intn(3) {
+		case 0:
+			b.WriteByte('-')
+		case 1:
+			b.WriteByte('+')
+		}
+		digits(1 + rnd.Intn(2))
+	}
+	return b.String()
+}
+
+func TestFastFloatRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	n := 500000
+	if testing.Short() {
+		n = 50000
+	}
+	fast := 0
+	for i := 0; i < n; i++ {
+		if checkFastFloat(t, randomDecimal(rnd)) {
+			fast++
+		}
+	}
+	// Formatted float64s must round trip through the fast path when they take it
+	for i := 0; i < n; i++ {
+		v := math.Float64frombits(rnd.Uint64())
+		for _, s := range []string{
+			strconv.FormatFloat(v, 'g', -1, 64),
+			strconv.FormatFloat(v, 'e', rnd.Intn(17), 64),
+			strconv.FormatFloat(rnd.NormFloat64()*1000, 'f', rnd.Intn(8), 64),
+		} {
+			if checkFastFloat(t, s) {
+				fast++
+			}
+		}
+	}
+	if fast == 0 {
+		t.Errorf("no input took the fast path")
+	}
+}
+
+var benchFloats = []string{"0.5", "-12.25", "3.14159", "1e-3", "12345", "6.02e23", "2.718281828459045", "-0.001"}
+
+func BenchmarkParseFloat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchFloats {
+			parseFloat(s)
+		}
+	}
+}
+
+func BenchmarkStrconvParseFloat(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchFloats {
+			strconv.ParseFloat(s, 64)
+		}
+	}
+}
+
+func BenchmarkReadAllSlice(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("a,b,c,d,e\n")
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		for j := 0; j < 5; j++ {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.FormatFloat(rnd.NormFloat64(), 'f', 6, 64))
+		}
+		buf.WriteByte('\n')
+	}
+	data := buf.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := NewReader(bytes.NewReader(data))
+		if _, err := r.ReadHeading(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := r.ReadAllSlice(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
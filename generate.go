@@ -0,0 +1,104 @@
+package numcsv
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+// Distribution is the distribution of the values written by Generate
+type Distribution int
+
+const (
+	DistUniform     Distribution = iota // uniform in [0, 1)
+	DistNormal                          // standard normal
+	DistExponential                     // exponential with rate 1
+	DistIntegers                        // uniform integers in [0, 1000)
+)
+
+// GenerateOptions configures the file written by Generate
+type GenerateOptions struct {
+	Rows, Cols   int
+	Distribution Distribution
+	Seed         int64 // seed of the random values. The same options always give the same file
+	Heading      bool  // write a heading line of c0, c1, ...
+	Comma        string
+	NaNRate      float64 // fraction of values written as NaNToken
+	NaNToken     string  // set to "NA" if ""
+
+	// Formatting quirks found in real files
+	Padding       bool // put random spaces around the fields
+	TrailingComma bool // end each line with a delimiter
+	CRLF          bool // end lines with \r\n
+	Comments      bool // put a comment line starting with # before the data
+}
+
+// Generate writes a CSV of random values, for benchmarking the Reader and
+// reproducing problems without sharing the real files.
+func Generate(w io.Writer, opt GenerateOptions) error {
+	bw := bufio.NewWriter(w)
+	rnd := rand.New(rand.NewSource(opt.Seed))
+	comma := opt.Comma
+	if comma == "" {
+		comma = ","
+	}
+	nan := opt.NaNToken
+	if nan == "" {
+		nan = "NA"
+	}
+	newline := "\n"
+	if opt.CRLF {
+		newline = "\r\n"
+	}
+	field := func(j int, str string) {
+		if j > 0 {
+			bw.WriteString(comma)
+		}
+		if opt.Padding {
+			bw.WriteString("  "[:rnd.Intn(3)])
+		}
+		bw.WriteString(str)
+		if opt.Padding {
+			bw.WriteString("  "[:rnd.Intn(3)])
+		}
+	}
+	end := func() {
+		if opt.TrailingComma {
+			bw.WriteString(comma)
+		}
+		bw.WriteString(newline)
+	}
+
+	if opt.Comments {
+		bw.WriteString("# generated by numcsv" + newline)
+	}
+	if opt.Heading {
+		for j := 0; j < opt.Cols; j++ {
+			field(j, "c"+strconv.Itoa(j))
+		}
+		end()
+	}
+	for i := 0; i < opt.Rows; i++ {
+		for j := 0; j < opt.Cols; j++ {
+			if opt.NaNRate > 0 && rnd.Float64() < opt.NaNRate {
+				field(j, nan)
+				continue
+			}
+			var v float64
+			switch opt.Distribution {
+			case DistNormal:
+				v = rnd.NormFloat64()
+			case DistExponential:
+				v = rnd.ExpFloat64()
+			case DistIntegers:
+				v = float64(rnd.Intn(1000))
+			default:
+				v = rnd.Float64()
+			}
+			field(j, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		end()
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,54 @@
+package numcsv
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	opt := GenerateOptions{
+		Rows: 50, Cols: 4, Distribution: DistIntegers, Seed: 3, Heading: true,
+		NaNRate: 0.1, Padding: true, TrailingComma: true, CRLF: true, Comments: true,
+	}
+	var a, b bytes.Buffer
+	if err := Generate(&a, opt); err != nil {
+		t.Fatal(err)
+	}
+	Generate(&b, opt)
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Errorf("same seed gave different files")
+	}
+
+	r := NewReader(&a)
+	r.Comment = "#"
+	r.NaNTokens = []string{"NA"}
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(headings, ","); got != "c0,c1,c2,c3" {
+		t.Errorf("headings = %q", headings)
+	}
+	if len(data) != opt.Rows {
+		t.Fatalf("%d records, want %d", len(data), opt.Rows)
+	}
+	nan := 0
+	for _, record := range data {
+		if len(record) != opt.Cols {
+			t.Fatalf("record %v, want %d values", record, opt.Cols)
+		}
+		for _, v := range record {
+			switch {
+			case math.IsNaN(v):
+				nan++
+			case v != math.Trunc(v) || v < 0 || v >= 1000:
+				t.Errorf("value %v is not an integer in [0, 1000)", v)
+			}
+		}
+	}
+	if nan == 0 {
+		t.Errorf("no missing values")
+	}
+}
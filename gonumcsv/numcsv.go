@@ -1,229 +1,139 @@
 // package gonumcsv is for reading numeric csv files into a matrix and writing
-// csv files from a matrix. gonumcsv is more tolerant
-// of formatting errors than encoding/csv files so it may be
-// of help with "from the wild" csv files who don't follow normal csv rules
+// csv files from a matrix. The parsing is done by numcsv, and the records
+// are converted to and from gonum matrices.
 
 package gonumcsv
 
 import (
-	"bufio"
-	"errors"
-	"io"
-	"strconv"
-	"strings"
-
-	"github.com/gonum/matrix/mat64"
-)
-
-// Reader reads in a csv file. Always ignores trailing comma
-type Reader struct {
-	Comma           rune   // field delimiter (set to ',' by NewReader)
-	HeadingComma    rune   // delimiter for the headings. If "", set to the same value as Comma
-	TrimWhitespace  bool   // trim whitespace around numbers and headers
-	Comment         string // comment character for start of line
-	FieldsPerRecord int    // If preset, the number of expected fields. Set otherwise
-	NoHeading       bool
-	hasEndingComma  bool
-	reader          io.Reader
-	scanner         *bufio.Scanner
-	lineRead        bool // signifier that some of the
-}
-
-func NewReader(r io.Reader) *Reader {
-	return &Reader{
-		Comma:   ",",
-		reader:  r,
-		scanner: bufio.NewScanner(r),
-	}
-}
-
-var (
-	ErrTrailingComma = errors.New("extra delimeter at end of line")
-	ErrFieldCount    = errors.New("wrong number of fields in line")
+	"github.com/btracey/numcsv"
+	"gonum.org/v1/gonum/mat"
 )
 
-// ReadHeading reads the string fields at the start, ignoring quotations if they are there
-func (r *Reader) ReadHeading() (headings []string, err error) {
-	// Read until prefix isn't comment
-	var line string
-	for b := r.scanner.Scan(); b; b = r.scanner.Scan() {
-		line = r.scanner.Text()
-		if line == "" {
-			continue
-		}
-		if r.Comment != "" && strings.HasPrefix(line, r.Comment) {
-			continue
-		}
-		break
-	}
-	if err := r.scanner.Err(); err != nil {
+// ReadAll reads all of the numeric records from the CSV into a matrix. ReadHeading
+// must be called first if there are headings. Errors are as in numcsv.Reader.ReadAllSlice.
+func ReadAll(r *numcsv.Reader) (*mat.Dense, error) {
+	data, err := r.ReadAllSlice()
+	if err != nil {
 		return nil, err
 	}
-	comma := r.HeadingComma
-	if comma == "" {
-		comma = r.Comma
-	}
-	strs := strings.Split(line, r.Comma)
-	for _, str := range strs {
-		str = strings.TrimSpace(str)
-		if len(str) != 0 {
-			headings = append(headings, str)
-		}
-	}
-
-	if r.FieldsPerRecord != 0 && len(headings) != r.FieldsPerRecord {
-		return nil, ErrFieldCount
-	}
-	r.FieldsPerRecord = len(headings)
-
-	// Remove the quotations
-	for i, str := range headings {
-		str = strings.TrimSuffix(str, "\"")
-		str = strings.TrimPrefix(str, "\"")
-		headings[i] = str
-	}
-	r.lineRead = true
-	return headings, nil
+	return NewDense(data), nil
 }
 
-// Read reads a single record from the CSV. ReadHeading must be called first if
-// there are headings. Returns nil if EOF reached.
-func (r *Reader) Read() ([]float64, error) {
-	b := r.scanner.Scan()
-	if !b {
-		return nil, r.scanner.Err()
-	}
-	line := r.scanner.Text()
-	allStrs := strings.Split(line, r.Comma)
-
-	strs := make([]string, 0, len(allStrs))
-	// Eliminate fields that are only whitespace
-	for _, str := range allStrs {
-		str = strings.TrimSpace(str)
-		if len(str) != 0 {
-			strs = append(strs, str)
-		}
-	}
-
-	if !r.lineRead {
-		r.lineRead = true
-		if r.FieldsPerRecord == 0 {
-			r.FieldsPerRecord = len(strs)
-		}
-	}
-
-	if len(strs) != r.FieldsPerRecord {
-		return nil, ErrFieldCount
-	}
-
-	// Parse all of the data
-	data := make([]float64, r.FieldsPerRecord)
-	var err error
-	for i, str := range strs {
-		data[i], err = strconv.ParseFloat(str, 64)
-		if err != nil {
-			return nil, err
-		}
+// ReadAllWithHeading reads the headings, unless they are not needed, and all
+// of the records into a matrix, as in numcsv.Reader.ReadAllWithHeading.
+func ReadAllWithHeading(r *numcsv.Reader) ([]string, *mat.Dense, error) {
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		return nil, nil, err
 	}
-	return data, nil
+	return headings, NewDense(data), nil
 }
 
-// ReadAll reads all of the numeric records from the CSV. ReadHeading must be called first if
-// there are headings
-func (r *Reader) ReadAll() (*mat64.Dense, error) {
-	alldata := make([][]float64, 0)
-	count := 0
-	for {
-		data, err := r.Read()
-		if err != nil {
-			return nil, err
-		}
-		if data == nil {
-			break
-		}
-		alldata = append(alldata, data)
-		count++
+// Load reads the headings and records of the CSV file at path into a matrix,
+// as in numcsv.Load.
+func Load(path string, opts ...numcsv.Option) ([]string, *mat.Dense, error) {
+	headings, data, err := numcsv.Load(path, opts...)
+	if err != nil {
+		return nil, nil, err
 	}
-	mat := mat64.NewDense(len(alldata), r.FieldsPerRecord, nil)
-	for i, record := range alldata {
-		for j, v := range record {
-			mat.Set(i, j, v)
-		}
-	}
-	return mat, nil
-}
-
-type Writer struct {
-	Comma        string
-	UseCRLF      bool
-	QuoteHeading bool // Put quotes around heading strings
-	FloatFmt     byte
-	w            *bufio.Writer
+	return headings, NewDense(data), nil
 }
 
-func NewWriter(w io.Writer) *Writer {
-	return &Writer{
-		Comma:    ",",
-		w:        bufio.NewWriter(w),
-		FloatFmt: 'e',
+// ReadAllStats is like ReadAll, but also returns statistics of the columns as
+// in numcsv.Reader.ReadAllStats.
+func ReadAllStats(r *numcsv.Reader) (*mat.Dense, *numcsv.Stats, error) {
+	data, stats, err := r.ReadAllStats()
+	if err != nil {
+		return nil, nil, err
 	}
+	return NewDense(data), stats, nil
 }
 
-func (w *Writer) WriteHeading(heading []string) (err error) {
-	for n, field := range heading {
-		if n > 0 {
-			if _, err = w.w.WriteString(w.Comma); err != nil {
-				return
+// ReadAllMasked is like ReadAll, but also returns a mask matrix which is 1
+// for the values present in the file and 0 for missing values, as in
+// numcsv.Reader.ReadAllMasked.
+func ReadAllMasked(r *numcsv.Reader) (data, mask *mat.Dense, err error) {
+	d, m, err := r.ReadAllMasked()
+	if err != nil {
+		return nil, nil, err
+	}
+	fm := make([][]float64, len(m))
+	for i, row := range m {
+		fm[i] = make([]float64, len(row))
+		for j, ok := range row {
+			if ok {
+				fm[i][j] = 1
 			}
 		}
-		if w.QuoteHeading {
-			field = "\"" + field + "\""
-		}
-		if _, err = w.w.WriteString(field); err != nil {
-			return
-		}
 	}
-	if w.UseCRLF {
-		_, err = w.w.WriteString("\r\n")
-	} else {
-		err = w.w.WriteByte('\n')
-	}
-	return err
+	return NewDense(d), NewDense(fm), nil
 }
 
-func (w *Writer) Write(record []float64) error {
-	for n, field := range record {
-		if n > 0 {
-			if _, err := w.w.WriteString(w.Comma); err != nil {
-				return err
-			}
-		}
-		str := strconv.FormatFloat(field, w.FloatFmt, 16, 64)
-		if _, err := w.w.WriteString(str); err != nil {
-			return err
-		}
-	}
-	var err error
-	if w.UseCRLF {
-		_, err = w.w.WriteString("\r\n")
-	} else {
-		err = w.w.WriteByte('\n')
+// ReadSample reads n records chosen uniformly at random from the rest of the
+// CSV into a matrix, as in numcsv.Reader.ReadSample
+func ReadSample(r *numcsv.Reader, n int, seed int64) (*mat.Dense, error) {
+	data, err := r.ReadSample(n, seed)
+	if err != nil {
+		return nil, err
 	}
-	return err
+	return NewDense(data), nil
 }
 
-func (w *Writer) WriteAll(headings []string, data *mat64.Dense) error {
+// WriteAll writes the headings, if not nil, and then the rows of data, and
+// flushes the output. data may be any matrix, such as a view, a transpose or
+// a symmetric matrix, and is not copied.
+func WriteAll(w *numcsv.Writer, headings []string, data mat.Matrix) error {
 	if headings != nil {
 		if err := w.WriteHeading(headings); err != nil {
 			return err
 		}
 	}
-	r, _ := data.Dims()
+	r, c := data.Dims()
+	rv, isRaw := data.(mat.RawRowViewer)
+	var row []float64
 	for i := 0; i < r; i++ {
-		err := w.Write(data.RowView(i))
-		if err != nil {
+		if isRaw {
+			row = rv.RawRowView(i)
+		} else {
+			if row == nil {
+				row = make([]float64, c)
+			}
+			for j := range row {
+				row[j] = data.At(i, j)
+			}
+		}
+		if err := w.Write(row); err != nil {
 			return err
 		}
 	}
-	return w.w.Flush()
+	return w.Flush()
+}
+
+// NewDense copies the records into a matrix. All of the records must have
+// the same length.
+func NewDense(data [][]float64) *mat.Dense {
+	if len(data) == 0 || len(data[0]) == 0 {
+		return &mat.Dense{}
+	}
+	c := len(data[0])
+	raw := make([]float64, 0, len(data)*c)
+	for _, record := range data {
+		if len(record) != c {
+			panic(mat.ErrShape)
+		}
+		raw = append(raw, record...)
+	}
+	return mat.NewDense(len(data), c, raw)
+}
+
+// FromTriplets returns the sparse matrix as a *mat.Dense
+func FromTriplets(t *numcsv.Triplets) *mat.Dense {
+	if t.Rows == 0 || t.Cols == 0 {
+		return &mat.Dense{}
+	}
+	m := mat.NewDense(t.Rows, t.Cols, nil)
+	for k, v := range t.V {
+		m.Set(t.I[k], t.J[k], v)
+	}
+	return m
 }
@@ -0,0 +1,81 @@
+package gonumcsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btracey/numcsv"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNewDense(t *testing.T) {
+	m := NewDense([][]float64{{1, 2, 3}, {4, 5, 6}})
+	if want := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}); !mat.Equal(m, want) {
+		t.Errorf("NewDense = %v, want %v", mat.Formatted(m), mat.Formatted(want))
+	}
+	for _, data := range [][][]float64{nil, {}, {{}, {}}} {
+		if !NewDense(data).IsEmpty() {
+			t.Errorf("NewDense(%v) is not empty", data)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ragged records: no panic")
+		}
+	}()
+	NewDense([][]float64{{1, 2}, {3}})
+}
+
+func TestFromTriplets(t *testing.T) {
+	m := FromTriplets(&numcsv.Triplets{Rows: 2, Cols: 3, I: []int{0, 1}, J: []int{2, 0}, V: []float64{5, 7}})
+	if want := mat.NewDense(2, 3, []float64{0, 0, 5, 7, 0, 0}); !mat.Equal(m, want) {
+		t.Errorf("FromTriplets = %v, want %v", mat.Formatted(m), mat.Formatted(want))
+	}
+	if !FromTriplets(&numcsv.Triplets{Rows: 0, Cols: 3}).IsEmpty() {
+		t.Errorf("FromTriplets of no rows is not empty")
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	r := numcsv.NewReader(strings.NewReader("a,b\n1,2\n3,4\n"))
+	headings, m, err := ReadAllWithHeading(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(headings) != 2 || !mat.Equal(m, mat.NewDense(2, 2, []float64{1, 2, 3, 4})) {
+		t.Errorf("ReadAllWithHeading = %q, %v", headings, mat.Formatted(m))
+	}
+
+	r = numcsv.NewReader(strings.NewReader("a,b\n"))
+	r.ReadHeading()
+	m, err = ReadAll(r)
+	if err != nil || !m.IsEmpty() {
+		t.Errorf("no records: ReadAll = %v, %v, want an empty matrix", m, err)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	d := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	sym := mat.NewSymDense(2, []float64{1, 2, 2, 3})
+	for _, test := range []struct {
+		name string
+		m    mat.Matrix
+		want string
+	}{
+		{"dense", d, "a\n1,2,3\n4,5,6\n"},
+		{"view", d.Slice(0, 2, 1, 3), "a\n2,3\n5,6\n"},
+		{"transpose", d.T(), "a\n1,4\n2,5\n3,6\n"},
+		{"symmetric", sym, "a\n1,2\n2,3\n"},
+		{"empty", &mat.Dense{}, "a\n"},
+	} {
+		var b strings.Builder
+		w := numcsv.NewWriter(&b)
+		w.FloatFmt, w.Precision = 'g', -1
+		if err := WriteAll(w, []string{"a"}, test.m); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if b.String() != test.want {
+			t.Errorf("%s: wrote %q, want %q", test.name, b.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package numcsv
+
+import (
+	"strings"
+	"unicode"
+)
+
+// HeadingClean is a set of clean-ups applied to the headings by ReadHeading,
+// making them safe to use as map keys and struct tags. The flags may be
+// combined.
+type HeadingClean int
+
+const (
+	CleanQuotes     HeadingClean = 1 << iota // remove all surrounding quotes, such as in "'name'" or ""name""
+	CleanLower                               // convert to lower case
+	CleanUnderscore                          // replace runs of spaces and punctuation with "_"
+	CleanAll        = CleanQuotes | CleanLower | CleanUnderscore
+)
+
+// CleanHeading applies the clean-ups to a heading. Surrounding whitespace is
+// always removed.
+func CleanHeading(heading string, c HeadingClean) string {
+	heading = strings.TrimSpace(heading)
+	if c&CleanQuotes != 0 {
+		for len(heading) >= 2 {
+			q := heading[0]
+			if (q != '"' && q != '\'' && q != '`') || heading[len(heading)-1] != q {
+				break
+			}
+			heading = strings.TrimSpace(heading[1 : len(heading)-1])
+		}
+	}
+	if c&CleanLower != 0 {
+		heading = strings.ToLower(heading)
+	}
+	if c&CleanUnderscore != 0 {
+		var b strings.Builder
+		sep := false
+		for _, r := range heading {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				if sep && b.Len() > 0 {
+					b.WriteByte('_')
+				}
+				sep = false
+				b.WriteRune(r)
+				continue
+			}
+			sep = true
+		}
+		heading = b.String()
+	}
+	return heading
+}
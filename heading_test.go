@@ -0,0 +1,49 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCleanHeading(t *testing.T) {
+	for _, test := range []struct {
+		heading string
+		clean   HeadingClean
+		want    string
+	}{
+		{"  Speed ", 0, "Speed"},
+		{`"Speed"`, 0, `"Speed"`},
+		{`"Speed"`, CleanQuotes, "Speed"},
+		{`" 'Speed' "`, CleanQuotes, "Speed"},
+		{`""Speed""`, CleanQuotes, "Speed"},
+		{"`Speed`", CleanQuotes, "Speed"},
+		{`"Speed'`, CleanQuotes, `"Speed'`},
+		{`"`, CleanQuotes, `"`},
+		{`""`, CleanQuotes, ""},
+		{"Wind Speed", CleanLower, "wind speed"},
+		{"Wind Speed (m/s)", CleanUnderscore, "Wind_Speed_m_s"},
+		{"  --a  .. b--  ", CleanUnderscore, "a_b"},
+		{"a_b", CleanUnderscore, "a_b"},
+		{"Température °C", CleanUnderscore, "Température_C"},
+		{"!!!", CleanUnderscore, ""},
+		{`"Wind Speed (m/s)"`, CleanAll, "wind_speed_m_s"},
+		{`"Wind Speed (m/s)"`, CleanLower | CleanUnderscore, "wind_speed_m_s"},
+	} {
+		if got := CleanHeading(test.heading, test.clean); got != test.want {
+			t.Errorf("CleanHeading(%q, %d) = %q, want %q", test.heading, test.clean, got, test.want)
+		}
+	}
+}
+
+func TestReaderCleanHeadings(t *testing.T) {
+	r := NewReader(strings.NewReader("\"Time (s)\",'Wind Speed'\n1,2\n"))
+	r.CleanHeadings = CleanAll
+	headings, err := r.ReadHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"time_s", "wind_speed"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+}
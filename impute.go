@@ -0,0 +1,105 @@
+package numcsv
+
+import (
+	"math"
+	"sort"
+)
+
+// Impute sets how ReadAllSlice and ReadTable replace missing (NaN) values
+type Impute int
+
+const (
+	ImputeNone     Impute = iota // keep missing values as NaN
+	ImputeDrop                   // drop records with a missing value
+	ImputeConstant               // replace missing values with Reader.ImputeValue
+	ImputeMean                   // replace missing values with the mean of the column
+	ImputeMedian                 // replace missing values with the median of the column
+)
+
+// imputer accumulates the column statistics needed to impute missing values
+// as the records are read
+type imputer struct {
+	mode  Impute
+	sum   []float64
+	count []int
+	skip  []bool // columns that are not imputed
+}
+
+// newImputer returns an imputer for records of n values. The labels of the
+// CategoricalColumns are not imputed, as a mean or constant is not a
+// category.
+func (r *Reader) newImputer(n int) *imputer {
+	imp := &imputer{
+		mode:  r.Impute,
+		sum:   make([]float64, n),
+		count: make([]int, n),
+		skip:  make([]bool, n),
+	}
+	for _, c := range r.cats {
+		imp.skip[r.outIndex[c.col]] = true
+	}
+	return imp
+}
+
+// add records the values of a record
+func (imp *imputer) add(data []float64) {
+	if imp.mode == ImputeMean {
+		for j, v := range data {
+			if !math.IsNaN(v) {
+				imp.sum[j] += v
+				imp.count[j]++
+			}
+		}
+	}
+}
+
+// hasNaN returns whether a record has a missing value, so is dropped by
+// ImputeDrop
+func hasNaN(data []float64) bool {
+	for _, v := range data {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// fill replaces the missing values in the records
+func (imp *imputer) fill(alldata [][]float64, value float64) {
+	for j := range imp.sum {
+		if imp.skip[j] {
+			continue
+		}
+		v := value
+		switch imp.mode {
+		case ImputeMean:
+			v = imp.sum[j] / float64(imp.count[j])
+		case ImputeMedian:
+			v = median(alldata, j)
+		}
+		for _, record := range alldata {
+			if math.IsNaN(record[j]) {
+				record[j] = v
+			}
+		}
+	}
+}
+
+// median returns the median of the values of column j that are not NaN
+func median(alldata [][]float64, j int) float64 {
+	vals := make([]float64, 0, len(alldata))
+	for _, record := range alldata {
+		if !math.IsNaN(record[j]) {
+			vals = append(vals, record[j])
+		}
+	}
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	sort.Float64s(vals)
+	n := len(vals)
+	if n%2 == 1 {
+		return vals[n/2]
+	}
+	return (vals[n/2-1] + vals[n/2]) / 2
+}
@@ -0,0 +1,45 @@
+package numcsv
+
+import "math"
+
+// ColumnType describes the values found in a column of the records. NaN values are
+// ignored, and all flags are false for a column with no other values.
+type ColumnType struct {
+	Integral bool // every value is an integer
+	Unit     bool // every value is within [0, 1]
+	Binary   bool // every value is 0 or 1
+}
+
+// InferTypes reports the type of the values in each column of the records.
+// It can be used to find columns that could be stored more compactly, or
+// that were written as floats by accident.
+func InferTypes(data [][]float64) []ColumnType {
+	if len(data) == 0 {
+		return nil
+	}
+	types := make([]ColumnType, len(data[0]))
+	for j := range types {
+		t := ColumnType{Integral: true, Unit: true, Binary: true}
+		n := 0
+		for _, record := range data {
+			v := record[j]
+			if math.IsNaN(v) {
+				continue
+			}
+			n++
+			if v != math.Trunc(v) || math.IsInf(v, 0) {
+				t.Integral = false
+			}
+			if v < 0 || v > 1 {
+				t.Unit = false
+			}
+			if v != 0 && v != 1 {
+				t.Binary = false
+			}
+		}
+		if n != 0 {
+			types[j] = t
+		}
+	}
+	return types
+}
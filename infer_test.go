@@ -0,0 +1,31 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestInferTypes(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	data := [][]float64{
+		{1, 0, 0.5, 2, nan, inf, -1},
+		{0, 1, 0.25, 3, nan, 1, 5},
+		{1, nan, 1, 4.5, nan, 2, 7},
+	}
+	want := []ColumnType{
+		{Integral: true, Unit: true, Binary: true},
+		{Integral: true, Unit: true, Binary: true},
+		{Unit: true},
+		{},
+		{},
+		{},
+		{Integral: true},
+	}
+	if got := InferTypes(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypes = %+v, want %+v", got, want)
+	}
+	if got := InferTypes(nil); got != nil {
+		t.Errorf("InferTypes(nil) = %+v, want nil", got)
+	}
+}
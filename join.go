@@ -0,0 +1,106 @@
+package numcsv
+
+import (
+	"fmt"
+	"math"
+)
+
+// JoinType sets which records are kept by Join
+type JoinType int
+
+const (
+	JoinInner JoinType = iota // records whose key is in both sources
+	JoinLeft                  // every record of the first source
+	JoinOuter                 // every record of either source
+)
+
+// Join reads all of the records of a and b, as in ReadAllWithHeading, and
+// merges the records with the same value in the column with the heading key,
+// such as a timestamp or sample ID. The merged records are the key, the other
+// columns of a, and then the other columns of b. A key that appears more than
+// once in either source gives every pairing of their records. Missing values
+// of records without a match are NaN. The records are in the order of a,
+// followed by the unmatched records of b for JoinOuter.
+func Join(a, b *Reader, key string, how JoinType) (headings []string, data [][]float64, err error) {
+	ha, da, err := a.ReadAllWithHeading()
+	if err != nil {
+		return nil, nil, err
+	}
+	hb, db, err := b.ReadAllWithHeading()
+	if err != nil {
+		return nil, nil, err
+	}
+	ka, kb := indexOf(ha, key), indexOf(hb, key)
+	if ka < 0 || kb < 0 {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownColumn, key)
+	}
+
+	headings = append(headings, key)
+	headings = append(headings, ha[:ka]...)
+	headings = append(headings, ha[ka+1:]...)
+	headings = append(headings, hb[:kb]...)
+	headings = append(headings, hb[kb+1:]...)
+	na, nb := len(ha)-1, len(hb)-1
+	nan := make([]float64, na+nb)
+	for j := range nan {
+		nan[j] = math.NaN()
+	}
+	join := func(k float64, ra, rb []float64) []float64 {
+		record := make([]float64, 0, 1+na+nb)
+		record = append(record, k)
+		if ra == nil {
+			record = append(record, nan[:na]...)
+		} else {
+			record = appendExcept(record, ra, ka)
+		}
+		if rb == nil {
+			record = append(record, nan[:nb]...)
+		} else {
+			record = appendExcept(record, rb, kb)
+		}
+		return record
+	}
+
+	byKey := make(map[float64][]int, len(db))
+	for i, rb := range db {
+		if k := rb[kb]; !math.IsNaN(k) {
+			byKey[k] = append(byKey[k], i)
+		}
+	}
+	matched := make([]bool, len(db))
+	data = make([][]float64, 0, len(da))
+	for _, ra := range da {
+		k := ra[ka]
+		rows := byKey[k]
+		for _, i := range rows {
+			matched[i] = true
+			data = append(data, join(k, ra, db[i]))
+		}
+		if len(rows) == 0 && how != JoinInner {
+			data = append(data, join(k, ra, nil))
+		}
+	}
+	if how == JoinOuter {
+		for i, rb := range db {
+			if !matched[i] {
+				data = append(data, join(rb[kb], nil, rb))
+			}
+		}
+	}
+	return headings, data, nil
+}
+
+func indexOf(headings []string, name string) int {
+	for j, h := range headings {
+		if h == name {
+			return j
+		}
+	}
+	return -1
+}
+
+// appendExcept appends the elements of src other than src[skip] to dst
+func appendExcept(dst, src []float64, skip int) []float64 {
+	dst = append(dst, src[:skip]...)
+	return append(dst, src[skip+1:]...)
+}
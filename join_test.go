@@ -0,0 +1,54 @@
+package numcsv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	// Key 2 is repeated in a and b, key 4 is only in a, key 5 only in b, and
+	// the NaN keys match nothing
+	const (
+		a = "x,k\n10,1\n20,2\n21,2\n40,4\n90,NA\n"
+		b = "k,y,z\n1,100,101\n2,200,201\n2,202,203\n5,500,501\nNA,900,901\n"
+	)
+	nan := math.NaN()
+	inner := [][]float64{
+		{1, 10, 100, 101},
+		{2, 20, 200, 201}, {2, 20, 202, 203},
+		{2, 21, 200, 201}, {2, 21, 202, 203},
+	}
+	left := append(append([][]float64{}, inner...), []float64{4, 40, nan, nan}, []float64{nan, 90, nan, nan})
+	outer := append(append([][]float64{}, left...), []float64{5, nan, 500, 501}, []float64{nan, nan, 900, 901})
+	for _, test := range []struct {
+		how  JoinType
+		want [][]float64
+	}{
+		{JoinInner, inner},
+		{JoinLeft, left},
+		{JoinOuter, outer},
+	} {
+		ra := NewReader(strings.NewReader(a))
+		rb := NewReader(strings.NewReader(b))
+		ra.NaNTokens = []string{"NA"}
+		rb.NaNTokens = []string{"NA"}
+		headings, data, err := Join(ra, rb, "k", test.how)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"k", "x", "y", "z"}; !reflect.DeepEqual(headings, want) {
+			t.Errorf("join %d: headings = %q, want %q", test.how, headings, want)
+		}
+		if !equalBits(data, test.want) {
+			t.Errorf("join %d: data = %v, want %v", test.how, data, test.want)
+		}
+	}
+
+	_, _, err := Join(NewReader(strings.NewReader(a), WithNaNTokens("NA")), NewReader(strings.NewReader(b), WithNaNTokens("NA")), "q", JoinInner)
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("unknown key: err = %v, want ErrUnknownColumn", err)
+	}
+}
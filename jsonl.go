@@ -0,0 +1,120 @@
+package numcsv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// WriteJSONL writes each record as a JSON object with the headings as keys,
+// one object per line. NaN and infinite values are written as null, since
+// JSON cannot represent them.
+func WriteJSONL(w io.Writer, headings []string, data [][]float64) error {
+	bw := bufio.NewWriter(w)
+	keys := make([]string, len(headings))
+	for j, h := range headings {
+		b, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		keys[j] = string(b)
+	}
+	for _, record := range data {
+		if len(record) != len(headings) {
+			return ErrFieldCount
+		}
+		bw.WriteByte('{')
+		for j, v := range record {
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(keys[j])
+			bw.WriteByte(':')
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				bw.WriteString("null")
+			} else {
+				bw.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		}
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
+
+// ReadJSONL reads newline-delimited JSON objects whose values are numbers or
+// null. The headings are the keys of the first object, in order. Keys missing
+// from later objects and null values are read as NaN, and keys not in the
+// first object are an error.
+func ReadJSONL(r io.Reader) (headings []string, data [][]float64, err error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	index := make(map[string]int)
+	for dec.More() {
+		obj, err := readJSONObject(dec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if headings == nil {
+			for _, kv := range obj {
+				index[kv.key] = len(headings)
+				headings = append(headings, kv.key)
+			}
+		}
+		record := make([]float64, len(headings))
+		for j := range record {
+			record[j] = math.NaN()
+		}
+		for _, kv := range obj {
+			j, ok := index[kv.key]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: record %d: %q", ErrUnknownColumn, len(data)+1, kv.key)
+			}
+			record[j] = kv.value
+		}
+		data = append(data, record)
+	}
+	return headings, data, nil
+}
+
+type jsonField struct {
+	key   string
+	value float64
+}
+
+// readJSONObject reads one object from dec, keeping the order of its keys
+func readJSONObject(dec *json.Decoder) ([]jsonField, error) {
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected JSON object, found %v", tok)
+	}
+	var obj []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		v := math.NaN()
+		switch tok := tok.(type) {
+		case nil:
+		case json.Number:
+			v, err = tok.Float64()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("value of %q is not a number", key)
+		}
+		obj = append(obj, jsonField{key: key, value: v})
+	}
+	_, err := dec.Token() // closing brace
+	return obj, err
+}
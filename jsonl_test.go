@@ -0,0 +1,59 @@
+package numcsv
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLRoundTrip(t *testing.T) {
+	headings := []string{"b", "a \"quoted\""}
+	data := [][]float64{{1.5, -2}, {1e-300, 0}}
+	var b bytes.Buffer
+	if err := WriteJSONL(&b, headings, data); err != nil {
+		t.Fatal(err)
+	}
+	gotHeadings, got, err := ReadJSONL(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeadings, headings) || !reflect.DeepEqual(got, data) {
+		t.Errorf("got %q, %v, want %q, %v", gotHeadings, got, headings, data)
+	}
+
+	if err := WriteJSONL(&b, headings, [][]float64{{1}}); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("short record: err = %v, want ErrFieldCount", err)
+	}
+}
+
+func TestJSONLMissing(t *testing.T) {
+	var b bytes.Buffer
+	WriteJSONL(&b, []string{"a", "b"}, [][]float64{{math.NaN(), math.Inf(1)}})
+	if got, want := b.String(), "{\"a\":null,\"b\":null}\n"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+
+	headings, data, err := ReadJSONL(strings.NewReader("{\"a\":1,\"b\":null}\n{\"b\":2}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nan := math.NaN()
+	if !reflect.DeepEqual(headings, []string{"a", "b"}) || !equalBits(data, [][]float64{{1, nan}, {nan, 2}}) {
+		t.Errorf("got %q, %v", headings, data)
+	}
+}
+
+func TestReadJSONLErrors(t *testing.T) {
+	_, _, err := ReadJSONL(strings.NewReader("{\"a\":1}\n{\"a\":2,\"c\":3}\n"))
+	if !errors.Is(err, ErrUnknownColumn) || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("unknown key: err = %v, want ErrUnknownColumn in record 2", err)
+	}
+	for _, input := range []string{"[1,2]\n", "{\"a\":\"x\"}\n", "{\"a\":{\"b\":1}}\n", "{\"a\":1\n"} {
+		if _, _, err := ReadJSONL(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: no error", input)
+		}
+	}
+}
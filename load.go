@@ -0,0 +1,31 @@
+package numcsv
+
+import "os"
+
+// ReadAllWithHeading reads the headings, unless NoHeading is set or they have
+// already been read, and then all of the records as in ReadAllSlice. The
+// returned headings are those of the columns of the records, as returned by
+// DataHeadings.
+func (r *Reader) ReadAllWithHeading() (headings []string, data [][]float64, err error) {
+	if !r.NoHeading && r.headings == nil {
+		if _, err := r.ReadHeading(); err != nil {
+			return nil, nil, err
+		}
+	}
+	data, err = r.ReadAllSlice()
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.DataHeadings(), data, nil
+}
+
+// Load reads the headings and records of the CSV file at path with a Reader
+// configured by the options, as in ReadAllWithHeading.
+func Load(path string, opts ...Option) (headings []string, data [][]float64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return NewReader(f, opts...).ReadAllWithHeading()
+}
@@ -0,0 +1,98 @@
+package numcsv
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(path, []byte("# a comment\nx;y\n1;NA\n2;4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	headings, data, err := Load(path, WithComma(";"), WithComment("#"), WithNaNTokens("NA"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "y"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if want := [][]float64{{1, math.NaN()}, {2, 4}}; !equalBits(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+
+	if _, _, err := Load(filepath.Join(dir, "missing.csv")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: error = %v, want %v", err, fs.ErrNotExist)
+	}
+
+	dup := filepath.Join(dir, "dup.csv")
+	if err := os.WriteFile(dup, []byte("a,a\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(dup, DialectStrict); !errors.Is(err, ErrDuplicateHeading) {
+		t.Errorf("duplicate headings: error = %v, want %v", err, ErrDuplicateHeading)
+	}
+}
+
+func TestReadAllWithHeading(t *testing.T) {
+	const input = "a,b\n1,2\n3,4\n"
+
+	// The heading is read if it has not been
+	r := NewReader(strings.NewReader(input))
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+
+	// A heading that has already been read is not read again
+	r = NewReader(strings.NewReader(input))
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	headings, data, err = r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings after ReadHeading = %q, want %q", headings, want)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data after ReadHeading = %v, want %v", data, want)
+	}
+
+	// With NoHeading every line is a record
+	r = NewReader(strings.NewReader("1,2\n3,4\n"), WithNoHeading())
+	headings, data, err = r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headings != nil {
+		t.Errorf("headings with NoHeading = %q, want nil", headings)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data with NoHeading = %v, want %v", data, want)
+	}
+
+	// Errors in the records are returned without any data
+	r = NewReader(strings.NewReader("a,b\n1,2\n3,x\n"))
+	headings, data, err = r.ReadAllWithHeading()
+	if err == nil {
+		t.Errorf("no error for a bad record")
+	}
+	if headings != nil || data != nil {
+		t.Errorf("bad record: got %q, %v, want nil", headings, data)
+	}
+}
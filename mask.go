@@ -0,0 +1,37 @@
+package numcsv
+
+// ReadAllMasked is like ReadAllSlice, but also returns a mask of the records
+// which is true for the values that were present in the file, and false for
+// missing values, which are NaN unless they are imputed. A value that is
+// written as NaN in the file is present. The uncertainty of a value without
+// one is missing, and every column of a one-hot column has the mask of the
+// column. Errors are returned as in ReadAllSlice.
+func (r *Reader) ReadAllMasked() (data [][]float64, mask [][]bool, err error) {
+	r.masking = true
+	defer func() { r.masking = false }()
+	var masks [][]float64
+	alldata, _, err := r.readRecords(func(i int, _ []float64) {
+		m := make([]float64, len(r.present))
+		for j, ok := range r.present {
+			if ok {
+				m[j] = 1
+			}
+		}
+		if i < len(masks) {
+			masks[i] = m
+		} else {
+			masks = append(masks, m)
+		}
+	}, false)
+	if err != nil {
+		return nil, nil, r.partialError(alldata, err)
+	}
+	for _, m := range r.rearrange(masks, true) {
+		b := make([]bool, len(m))
+		for j, v := range m {
+			b[j] = v == 1
+		}
+		mask = append(mask, b)
+	}
+	return r.arrange(alldata), mask, nil
+}
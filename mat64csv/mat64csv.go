@@ -0,0 +1,72 @@
+// package mat64csv adapts numcsv to the deprecated github.com/gonum/matrix/mat64
+// package, for code that has not yet moved to gonum.org/v1/gonum/mat
+
+package mat64csv
+
+import (
+	"github.com/btracey/numcsv"
+	"github.com/btracey/numcsv/gonumcsv"
+	"github.com/gonum/matrix/mat64"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadAll reads all of the numeric records from the CSV as in gonumcsv.ReadAll
+func ReadAll(r *numcsv.Reader) (*mat64.Dense, error) {
+	m, err := gonumcsv.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromMat(m), nil
+}
+
+// WriteAll writes the headings and data as in gonumcsv.WriteAll. data may be
+// any matrix, such as a view, a transpose or a symmetric matrix.
+func WriteAll(w *numcsv.Writer, headings []string, data mat64.Matrix) error {
+	if d, ok := data.(*mat64.Dense); ok {
+		return gonumcsv.WriteAll(w, headings, ToMat(d))
+	}
+	return gonumcsv.WriteAll(w, headings, matrix{data})
+}
+
+// matrix adapts a mat64.Matrix to a mat.Matrix
+type matrix struct {
+	m mat64.Matrix
+}
+
+func (m matrix) Dims() (r, c int)    { return m.m.Dims() }
+func (m matrix) At(i, j int) float64 { return m.m.At(i, j) }
+func (m matrix) T() mat.Matrix       { return mat.Transpose{Matrix: m} }
+
+// FromMat returns m as a *mat64.Dense. The data is shared unless m is a view
+// of part of a larger matrix.
+func FromMat(m *mat.Dense) *mat64.Dense {
+	raw := m.RawMatrix()
+	if raw.Rows == 0 || raw.Cols == 0 {
+		return mat64.NewDense(0, 0, nil)
+	}
+	if raw.Stride == raw.Cols {
+		return mat64.NewDense(raw.Rows, raw.Cols, raw.Data[:raw.Rows*raw.Cols])
+	}
+	d := mat64.NewDense(raw.Rows, raw.Cols, nil)
+	for i := 0; i < raw.Rows; i++ {
+		copy(d.RawRowView(i), m.RawRowView(i))
+	}
+	return d
+}
+
+// ToMat returns m as a *mat.Dense. The data is shared unless m is a view of
+// part of a larger matrix.
+func ToMat(m *mat64.Dense) *mat.Dense {
+	raw := m.RawMatrix()
+	if raw.Rows == 0 || raw.Cols == 0 {
+		return &mat.Dense{}
+	}
+	if raw.Stride == raw.Cols {
+		return mat.NewDense(raw.Rows, raw.Cols, raw.Data[:raw.Rows*raw.Cols])
+	}
+	d := mat.NewDense(raw.Rows, raw.Cols, nil)
+	for i := 0; i < raw.Rows; i++ {
+		copy(d.RawRowView(i), m.RawRowView(i))
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package mat64csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btracey/numcsv"
+	"github.com/gonum/matrix/mat64"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFromMat(t *testing.T) {
+	m := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	d := FromMat(m)
+	if !mat64.Equal(d, mat64.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})) {
+		t.Errorf("FromMat = %v", mat64.Formatted(d))
+	}
+	// The data is shared
+	m.Set(0, 0, 9)
+	if d.At(0, 0) != 9 {
+		t.Errorf("FromMat does not share the data")
+	}
+	// A view is copied
+	v := FromMat(m.Slice(0, 2, 1, 3).(*mat.Dense))
+	if !mat64.Equal(v, mat64.NewDense(2, 2, []float64{2, 3, 5, 6})) {
+		t.Errorf("FromMat of a view = %v", mat64.Formatted(v))
+	}
+	if r, c := FromMat(&mat.Dense{}).Dims(); r != 0 || c != 0 {
+		t.Errorf("FromMat of an empty matrix has dims %d, %d", r, c)
+	}
+}
+
+func TestToMat(t *testing.T) {
+	d := mat64.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	m := ToMat(d)
+	if !mat.Equal(m, mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})) {
+		t.Errorf("ToMat = %v", mat.Formatted(m))
+	}
+	d.Set(1, 2, 9)
+	if m.At(1, 2) != 9 {
+		t.Errorf("ToMat does not share the data")
+	}
+	v := ToMat(d.View(1, 1, 1, 2).(*mat64.Dense))
+	if !mat.Equal(v, mat.NewDense(1, 2, []float64{5, 9})) {
+		t.Errorf("ToMat of a view = %v", mat.Formatted(v))
+	}
+	if !ToMat(mat64.NewDense(0, 0, nil)).IsEmpty() {
+		t.Errorf("ToMat of an empty matrix is not empty")
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	r := numcsv.NewReader(strings.NewReader("a,b\n1,2\n3,4\n"))
+	r.ReadHeading()
+	d, err := ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat64.Equal(d, mat64.NewDense(2, 2, []float64{1, 2, 3, 4})) {
+		t.Errorf("ReadAll = %v", mat64.Formatted(d))
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	d := mat64.NewDense(2, 2, []float64{1, 2, 3, 4})
+	for _, test := range []struct {
+		name string
+		m    mat64.Matrix
+		want string
+	}{
+		{"dense", d, "1,2\n3,4\n"},
+		{"transpose", d.T(), "1,3\n2,4\n"},
+		{"symmetric", mat64.NewSymDense(2, []float64{1, 5, 5, 2}), "1,5\n5,2\n"},
+	} {
+		var b strings.Builder
+		w := numcsv.NewWriter(&b)
+		w.FloatFmt, w.Precision = 'g', -1
+		if err := WriteAll(w, nil, test.m); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if b.String() != test.want {
+			t.Errorf("%s: wrote %q, want %q", test.name, b.String(), test.want)
+		}
+	}
+}
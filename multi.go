@@ -0,0 +1,71 @@
+package numcsv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NewMultiReader returns a Reader reading the files one after another as a
+// single dataset, such as an experiment split across daily files. If
+// ReadHeading is called, the headings of each later file are read, and
+// ErrHeadingMismatch is returned if they are not the same as the headings of
+// the first. Line numbers count the lines of all of the files read so far.
+// If the times of a time column of a file start at or before the last time
+// of the file before it, as when each file starts from zero, they are offset
+// to continue one time step after it, as by Reader.ContinueTime.
+func NewMultiReader(readers ...io.Reader) *Reader {
+	if len(readers) == 0 {
+		return NewReader(strings.NewReader(""))
+	}
+	r := NewReader(readers[0])
+	r.more = readers[1:]
+	for _, rd := range r.more {
+		if n := inputSize(rd); n < 0 || r.total < 0 {
+			r.total = -1
+		} else {
+			r.total += n
+		}
+	}
+	return r
+}
+
+// nextFile starts reading the next file given to NewMultiReader. Returns false if
+// there are no more files or its headings could not be read.
+func (r *Reader) nextFile() bool {
+	if len(r.more) == 0 || r.fileErr != nil || r.scanner.Err() != nil {
+		return false
+	}
+	r.reader = r.more[0]
+	r.counter.r = r.more[0]
+	r.newScanner()
+	r.more = r.more[1:]
+	r.scanning = false
+	r.ended = false
+	r.file++
+	if r.rawHeadings == nil {
+		return true
+	}
+	headings, err := r.scanHeading()
+	if err != nil {
+		r.fileErr = err
+		return false
+	}
+	if !equalStrings(headings, r.rawHeadings) {
+		r.fileErr = fmt.Errorf("line %d: %w", r.line, ErrHeadingMismatch)
+		return false
+	}
+	return true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMultiReaderTime(t *testing.T) {
+	const restart = "t,v\n2020-01-01T00:00:00Z,1\n2020-01-01T00:00:01Z,2\n"
+	for _, test := range []struct {
+		name  string
+		files []string
+		want  [][]float64
+	}{
+		{"restart", []string{restart, restart}, [][]float64{{0, 1}, {1, 2}, {2, 1}, {3, 2}}},
+		{"gap kept", []string{restart, "t,v\n2020-01-01T00:00:10Z,3\n"}, [][]float64{{0, 1}, {1, 2}, {10, 3}}},
+	} {
+		r := NewMultiReader(strings.NewReader(test.files[0]), strings.NewReader(test.files[1]))
+		r.TimeColumns = []TimeColumn{{Name: "t", SinceFirst: true}}
+		if _, err := r.ReadHeading(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := r.ReadAllSlice()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(data, test.want) {
+			t.Errorf("%s: data = %v, want %v", test.name, data, test.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package numcsv
+
+import "math"
+
+// Normalize sets the scaling of the columns applied by ReadAllSlice and ReadTable.
+// Each value v of a column is replaced by (v - offset) / scale.
+type Normalize int
+
+const (
+	NormalizeNone   Normalize = iota
+	NormalizeZScore           // offset is the mean and scale is the standard deviation
+	NormalizeMinMax           // offset is the minimum and scale is the range, giving values in [0, 1]
+)
+
+// Normalization returns the offset and scale of each column of the records
+// returned by Read used by the last call to ReadAllSlice or ReadTable. Returns nil
+// if Normalize is NormalizeNone. The index column added by AddIndex and the
+// labels and one-hot columns of the CategoricalColumns are not scaled.
+func (r *Reader) Normalization() (offset, scale []float64) {
+	return r.offset, r.scale
+}
+
+func (r *Reader) normalize(alldata [][]float64, stats *Stats) {
+	n := len(stats.Count)
+	r.offset = make([]float64, n)
+	r.scale = make([]float64, n)
+	skip := make([]bool, n)
+	if r.AddIndex {
+		skip[0] = true
+	}
+	for _, c := range r.cats {
+		skip[r.outIndex[c.col]] = true
+	}
+	for j := range r.offset {
+		offset, scale := 0.0, 1.0
+		if !skip[j] {
+			switch r.Normalize {
+			case NormalizeZScore:
+				offset, scale = stats.Mean[j], stats.Std[j]
+			case NormalizeMinMax:
+				offset, scale = stats.Min[j], stats.Max[j]-stats.Min[j]
+			}
+			// Leave constant columns unscaled
+			if scale == 0 || math.IsNaN(scale) {
+				scale = 1
+			}
+		}
+		r.offset[j] = offset
+		r.scale[j] = scale
+	}
+	for _, record := range alldata {
+		for j, v := range record {
+			record[j] = (v - r.offset[j]) / r.scale[j]
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	const input = "c,a,k\nx,1,5\ny,2,5\nx,3,5\n"
+	for _, test := range []struct {
+		normalize Normalize
+		a         []float64
+	}{
+		{NormalizeZScore, []float64{-1, 0, 1}},
+		{NormalizeMinMax, []float64{0, 0.5, 1}},
+	} {
+		r := NewReader(strings.NewReader(input))
+		r.AddIndex = true
+		r.CategoricalColumns = []CategoricalColumn{{Name: "c"}}
+		r.Normalize = test.normalize
+		r.ReadHeading()
+		data, err := r.ReadAllSlice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The index and labels are unscaled, and the constant column is
+		// only offset
+		want := [][]float64{{0, 0, test.a[0], 0}, {1, 1, test.a[1], 0}, {2, 0, test.a[2], 0}}
+		if !reflect.DeepEqual(data, want) {
+			t.Errorf("normalize %d: data = %v, want %v", test.normalize, data, want)
+		}
+		offset, scale := r.Normalization()
+		if offset[0] != 0 || scale[0] != 1 || offset[1] != 0 || scale[1] != 1 {
+			t.Errorf("normalize %d: offset = %v, scale = %v", test.normalize, offset, scale)
+		}
+	}
+
+	r := NewReader(strings.NewReader("c,a\nx,1\ny,3\n"))
+	r.CategoricalColumns = []CategoricalColumn{{Name: "c", OneHot: true}}
+	r.Normalize = NormalizeZScore
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	std := math.Sqrt(2)
+	s := 1 / std
+	if want := [][]float64{{1, 0, -s}, {0, 1, s}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("one-hot: data = %v, want %v", data, want)
+	}
+}
@@ -0,0 +1,235 @@
+package numcsv
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errNPY = errors.New("not a supported .npy file")
+
+// maxNPYEmptyRows is the largest number of rows read from an array with no
+// columns. Such rows hold no data, so the file cannot bound their number.
+const maxNPYEmptyRows = 1 << 20
+
+// WriteNPY writes the records as a 2-D float64 array in the NumPy .npy
+// format, which can be loaded in Python with numpy.load.
+func WriteNPY(w io.Writer, data [][]float64) error {
+	rows, cols := len(data), 0
+	if rows > 0 {
+		cols = len(data[0])
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d, %d), }", rows, cols)
+	// Pad the header with spaces so the data is aligned to 64 bytes
+	n := 10 + len(header) + 1
+	header += strings.Repeat(" ", (64-n%64)%64) + "\n"
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(bw, binary.LittleEndian, uint16(len(header)))
+	bw.WriteString(header)
+	var buf [8]byte
+	for _, record := range data {
+		if len(record) != cols {
+			return ErrFieldCount
+		}
+		for _, v := range record {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			bw.Write(buf[:])
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadNPY reads a 1-D or 2-D array in the NumPy .npy format. The array may
+// hold float64, float32, int64 or int32 values in either byte order, and
+// either C or Fortran order. A 1-D array is read as a single column.
+func ReadNPY(r io.Reader) ([][]float64, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, 8)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if string(magic[:6]) != "\x93NUMPY" {
+		return nil, errNPY
+	}
+	var hlen int
+	switch magic[6] {
+	case 1:
+		var n uint16
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		hlen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		hlen = int(n)
+	default:
+		return nil, errNPY
+	}
+	header := make([]byte, hlen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, err
+	}
+	descr, fortran, shape, err := parseNPYHeader(string(header))
+	if err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if descr[0] == '>' {
+		order = binary.BigEndian
+	}
+	var size int
+	var conv func([]byte) float64
+	switch descr[1:] {
+	case "f8":
+		size, conv = 8, func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }
+	case "f4":
+		size, conv = 4, func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }
+	case "i8":
+		size, conv = 8, func(b []byte) float64 { return float64(int64(order.Uint64(b))) }
+	case "i4":
+		size, conv = 4, func(b []byte) float64 { return float64(int32(order.Uint32(b))) }
+	default:
+		return nil, fmt.Errorf("%w: dtype %s", errNPY, descr)
+	}
+
+	rows, cols := shape[0], 1
+	if len(shape) == 2 {
+		cols = shape[1]
+	}
+	if (cols > 0 && rows > math.MaxInt/size/cols) || (cols == 0 && rows > maxNPYEmptyRows) {
+		return nil, fmt.Errorf("%w: shape %v", errNPY, shape)
+	}
+	// Grow the values as they are read, so a corrupt shape cannot
+	// allocate more than the file holds
+	n := rows * cols
+	hint := n
+	if hint > 1<<16 {
+		hint = 1 << 16
+	}
+	raw := make([]float64, 0, hint)
+	buf := make([]byte, size)
+	for k := 0; k < n; k++ {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, err
+		}
+		raw = append(raw, conv(buf))
+	}
+	if fortran {
+		c := make([]float64, n)
+		for k, v := range raw {
+			c[(k%rows)*cols+k/rows] = v
+		}
+		raw = c
+	}
+	data := make([][]float64, rows)
+	for i := range data {
+		data[i] = raw[i*cols : (i+1)*cols]
+	}
+	return data, nil
+}
+
+// parseNPYHeader parses the Python dict literal at the start of a .npy file
+func parseNPYHeader(h string) (descr string, fortran bool, shape []int, err error) {
+	value := func(key string) string {
+		i := strings.Index(h, "'"+key+"'")
+		if i < 0 {
+			return ""
+		}
+		v := strings.TrimSpace(h[i+len(key)+2:])
+		return strings.TrimSpace(strings.TrimPrefix(v, ":"))
+	}
+	d := value("descr")
+	if len(d) < 2 || d[0] != '\'' {
+		return "", false, nil, errNPY
+	}
+	if end := strings.IndexByte(d[1:], '\''); end >= 0 {
+		descr = d[1 : end+1]
+	}
+	if len(descr) != 3 || strings.IndexByte("<>|=", descr[0]) < 0 {
+		return "", false, nil, fmt.Errorf("%w: dtype %s", errNPY, descr)
+	}
+	if descr[0] == '|' || descr[0] == '=' {
+		descr = "<" + descr[1:]
+	}
+	fortran = strings.HasPrefix(value("fortran_order"), "True")
+	s := value("shape")
+	end := strings.IndexByte(s, ')')
+	if !strings.HasPrefix(s, "(") || end < 0 {
+		return "", false, nil, errNPY
+	}
+	for _, f := range strings.Split(s[1:end], ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return "", false, nil, errNPY
+		}
+		shape = append(shape, n)
+	}
+	if len(shape) != 1 && len(shape) != 2 {
+		return "", false, nil, fmt.Errorf("%w: %d dimensions", errNPY, len(shape))
+	}
+	return descr, fortran, shape, nil
+}
+
+// WriteNPZ writes the arrays as a NumPy .npz archive. Each array is stored as
+// name.npy.
+func WriteNPZ(w io.Writer, arrays map[string][][]float64) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := WriteNPY(f, arrays[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ReadNPZ reads the arrays in a NumPy .npz archive of the given size. The
+// arrays are keyed by their names without the .npy extension.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string][][]float64, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	arrays := make(map[string][][]float64)
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".npy") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ReadNPY(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = data
+	}
+	return arrays, nil
+}
@@ -0,0 +1,214 @@
+package numcsv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// npyFile returns a .npy file with the given header and data, in format
+// version 1 or 2
+func npyFile(version byte, header string, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("\x93NUMPY")
+	b.WriteByte(version)
+	b.WriteByte(0)
+	if version == 1 {
+		binary.Write(&b, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(&b, binary.LittleEndian, uint32(len(header)))
+	}
+	b.WriteString(header)
+	b.Write(data)
+	return b.Bytes()
+}
+
+// equalBits returns whether a and b have the same shape and identical values,
+// including NaNs and signed zeros
+func equalBits(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if math.Float64bits(a[i][j]) != math.Float64bits(b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestNPYRoundTrip(t *testing.T) {
+	for _, data := range [][][]float64{
+		{{1, 2, 3}, {4, 5, 6}},
+		{{math.NaN(), math.Inf(1)}, {math.Inf(-1), math.Copysign(0, -1)}, {math.MaxFloat64, math.SmallestNonzeroFloat64}},
+		{{1}},
+		{},
+	} {
+		var b bytes.Buffer
+		if err := WriteNPY(&b, data); err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, record := range data {
+			n += 8 * len(record)
+		}
+		if (b.Len()-n)%64 != 0 {
+			t.Errorf("data of %v is not aligned to 64 bytes", data)
+		}
+		got, err := ReadNPY(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalBits(got, data) {
+			t.Errorf("ReadNPY = %v, want %v", got, data)
+		}
+	}
+}
+
+func TestWriteNPYRagged(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteNPY(&b, [][]float64{{1, 2}, {3}}); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("err = %v, want ErrFieldCount", err)
+	}
+}
+
+func TestReadNPY(t *testing.T) {
+	le, be := binary.LittleEndian, binary.BigEndian
+	f4 := func(order binary.ByteOrder, vs ...float32) []byte {
+		b := make([]byte, 4*len(vs))
+		for i, v := range vs {
+			order.PutUint32(b[4*i:], math.Float32bits(v))
+		}
+		return b
+	}
+	i4 := func(order binary.ByteOrder, vs ...int32) []byte {
+		b := make([]byte, 4*len(vs))
+		for i, v := range vs {
+			order.PutUint32(b[4*i:], uint32(v))
+		}
+		return b
+	}
+	i8 := func(order binary.ByteOrder, vs ...int64) []byte {
+		b := make([]byte, 8*len(vs))
+		for i, v := range vs {
+			order.PutUint64(b[8*i:], uint64(v))
+		}
+		return b
+	}
+	for _, test := range []struct {
+		name string
+		file []byte
+		want [][]float64
+	}{
+		{
+			"float32 big-endian",
+			npyFile(1, "{'descr': '>f4', 'fortran_order': False, 'shape': (2, 2), }\n", f4(be, 1.5, -2, 3, 4)),
+			[][]float64{{1.5, -2}, {3, 4}},
+		},
+		{
+			"int32",
+			npyFile(1, "{'descr': '<i4', 'fortran_order': False, 'shape': (1, 3), }\n", i4(le, -1, 0, 7)),
+			[][]float64{{-1, 0, 7}},
+		},
+		{
+			"int64 big-endian",
+			npyFile(1, "{'descr': '>i8', 'fortran_order': False, 'shape': (2, 1), }\n", i8(be, -5, 1<<40)),
+			[][]float64{{-5}, {1 << 40}},
+		},
+		{
+			"fortran order",
+			npyFile(1, "{'descr': '<i4', 'fortran_order': True, 'shape': (2, 3), }\n", i4(le, 1, 4, 2, 5, 3, 6)),
+			[][]float64{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			"1-D",
+			npyFile(1, "{'descr': '<i4', 'fortran_order': False, 'shape': (3,), }\n", i4(le, 1, 2, 3)),
+			[][]float64{{1}, {2}, {3}},
+		},
+		{
+			"version 2, keys reordered",
+			npyFile(2, "{'shape': (1, 2), 'fortran_order': False, 'descr': '<f4'}\n", f4(le, 0.25, 8)),
+			[][]float64{{0.25, 8}},
+		},
+		{
+			"native byte order",
+			npyFile(1, "{'descr': '=i4', 'fortran_order': False, 'shape': (1, 1), }\n", i4(le, 9)),
+			[][]float64{{9}},
+		},
+		{
+			"empty",
+			npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (0, 4), }\n", nil),
+			[][]float64{},
+		},
+	} {
+		got, err := ReadNPY(bytes.NewReader(test.file))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadNPYErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		file []byte
+	}{
+		{"empty", nil},
+		{"bad magic", []byte("\x93NUMPX\x01\x00\x00\x00")},
+		{"bad version", npyFile(4, "{}", nil)},
+		{"truncated header", npyFile(1, "{'descr': '<f8'", nil)[:12]},
+		{"complex", npyFile(1, "{'descr': '<c16', 'fortran_order': False, 'shape': (1, 1), }\n", make([]byte, 16))},
+		{"object", npyFile(1, "{'descr': '|O', 'fortran_order': False, 'shape': (1, 1), }\n", make([]byte, 8))},
+		{"no descr", npyFile(1, "{'fortran_order': False, 'shape': (1, 1), }\n", make([]byte, 8))},
+		{"3-D", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }\n", make([]byte, 8))},
+		{"0-D", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (), }\n", make([]byte, 8))},
+		{"negative shape", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (-1, 2), }\n", nil)},
+		{"oversized empty shape", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (4611686018427387904, 0), }\n", nil)},
+		{"large empty shape", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (1099511627776, 0), }\n", nil)},
+		{"bad shape", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (a, 2), }\n", nil)},
+		{"truncated data", npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2), }\n", make([]byte, 24))},
+	} {
+		if _, err := ReadNPY(bytes.NewReader(test.file)); err == nil {
+			t.Errorf("%s: no error", test.name)
+		}
+	}
+}
+
+func TestNPZRoundTrip(t *testing.T) {
+	arrays := map[string][][]float64{
+		"x":     {{1, 2}, {3, 4}},
+		"noise": {{math.NaN()}, {-1}},
+	}
+	var b bytes.Buffer
+	if err := WriteNPZ(&b, arrays); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(arrays) {
+		t.Fatalf("got %d arrays, want %d", len(got), len(arrays))
+	}
+	for name, want := range arrays {
+		if !equalBits(got[name], want) {
+			t.Errorf("%s = %v, want %v", name, got[name], want)
+		}
+	}
+	if _, err := ReadNPZ(strings.NewReader("not a zip"), 9); err == nil {
+		t.Errorf("ReadNPZ of a non-zip file: no error")
+	}
+}
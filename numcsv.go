@@ -1,17 +1,19 @@
 // package numcsv is for reading numeric csv files. It is more tolerant
 // of errors in formatting than the standard go encoding/csv files so it may be
-// of help with "from the wild" csv files who don't follow normal csv rules
+// of help with "from the wild" csv files who don't follow normal csv rules.
+// numcsv has no dependencies outside the standard library, and the records are
+// read into a [][]float64. The gonumcsv package reads them into a matrix.
 
 package numcsv
 
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
-
-	"github.com/gonum/matrix/mat64"
 )
 
 type Reader struct {
@@ -21,30 +23,381 @@ type Reader struct {
 	Comment         string // comment character for start of line
 	FieldsPerRecord int    // If preset, the number of expected fields. Set otherwise
 	NoHeading       bool
-	hasEndingComma  bool
-	reader          io.Reader
-	scanner         *bufio.Scanner
-	lineRead        bool // signifier that some of the
+	Splitter        Splitter // splits the lines into fields. If nil, lines are split at Comma
+	MaxLineSize     int      // maximum length of a line in bytes. If 0, bufio.MaxScanTokenSize is used
+
+	// ParseUnits finds the units at the end of the headings, such as
+	// "Velocity (m/s)" or "Temp [K]". The units are returned by Units, and are
+	// removed from the headings if StripUnits is set.
+	ParseUnits bool
+	StripUnits bool
+
+	// UnitConversions converts the values of the columns with the given
+	// units when ParseUnits is set, before the Converters are applied. For
+	// example, {"mph": {To: "m/s", Scale: 0.44704}}.
+	UnitConversions map[string]UnitConversion
+
+	TimeColumns   []TimeColumn // columns containing timestamps instead of numbers
+	StringColumns []Column     // columns containing strings. They are omitted from Read and kept by ReadTable
+
+	// UncertainColumns contain values with an uncertainty, such as
+	// "1.23±0.05" or "1.23+/-0.05". Each is read as two values, the value and
+	// the uncertainty, and the heading of the uncertainty is the heading of
+	// the column with "_sigma" appended. The uncertainty of a value written
+	// without one is NaN.
+	UncertainColumns []Column
+
+	// FractionColumns contain fractions such as "3/4" or "1 1/2", or ratios
+	// such as "1:250", which are read as their value.
+	FractionColumns []Column
+
+	// IntColumns contain integers, which may also be written in hex (0x1F),
+	// binary (0b1010) or octal (0o17). Their exact values are kept by ReadTable.
+	IntColumns []Column
+
+	// BoolColumns contain boolean tokens, which are read as 1 or 0. The tokens
+	// are given by BoolTokens, or by DefaultBoolTokens if BoolTokens is nil.
+	// Tokens are matched ignoring case.
+	BoolColumns []Column
+	BoolTokens  map[string]bool
+
+	// DetectStrings sets any column whose first value that is not missing
+	// (one of the NaNTokens) is not a number to be a string column. If the
+	// first record has missing values, the records after it are read ahead
+	// until each column has a value.
+	DetectStrings bool
+
+	// AddIndex prepends a column numbering the records starting from
+	// IndexStart. Its heading is IndexHeading, or "index" if IndexHeading is "".
+	AddIndex     bool
+	IndexStart   int
+	IndexHeading string
+
+	Checks     []Check     // assertions each record must satisfy
+	Converters []Converter // applied in order to every parsed value
+
+	// Transform, if not nil, is applied to every parsed value after the
+	// Converters, as by a ConverterFunc. Like the Converters, it is not
+	// applied to the values of BoolColumns and CategoricalColumns.
+	Transform func(col int, v float64) float64
+
+	EmptyRows EmptyRowPolicy // handling of blank lines and lines of only delimiters
+
+	// Normalize scales the columns of the records returned by ReadAllSlice and
+	// ReadTable. The offsets and scales used are returned by Normalization.
+	Normalize Normalize
+
+	// ColumnOrder, if not nil, selects and orders the columns of the records
+	// returned by ReadAllSlice and ReadTable by their headings, so that files
+	// with the same columns in different orders are read identically. The
+	// headings are those of the values returned by Read, and a one-hot
+	// column is selected by its heading before expansion.
+	ColumnOrder []string
+
+	// Transpose returns the transpose of the records from ReadAllSlice and
+	// ReadTable, for files with one variable per line. The headings then
+	// label the rows of the result.
+	Transpose bool
+
+	// NaNTokens are read as NaN, marking a missing value. Missing values
+	// are replaced in ReadAllSlice and ReadTable as set by Impute.
+	NaNTokens   []string
+	Impute      Impute
+	ImputeValue float64 // value of missing values for ImputeConstant
+
+	CategoricalColumns []CategoricalColumn // columns of string values read as integer labels
+	DuplicateHeadings  DuplicatePolicy     // handling of columns with the same heading
+	CleanHeadings      HeadingClean        // clean-ups applied to the headings, after the units are found
+
+	// MaxRows, if positive, limits the number of records returned by
+	// ReadAllSlice, ReadTable and ReadAllStats. The rest of the file is not
+	// read, so a large file can be previewed quickly.
+	MaxRows int
+
+	// RowFilter, if not nil, is called with each record read by ReadAllSlice,
+	// ReadTable and ReadAllStats, and only the records for which it returns
+	// true are kept. The records are filtered before missing values are
+	// imputed.
+	RowFilter func(row []float64) bool
+
+	// Dedupe drops records from ReadAllSlice, ReadTable and ReadAllStats that
+	// duplicate an earlier record. Records are duplicates if they have the
+	// same value in the column with the heading DedupeKey, or if DedupeKey is
+	// "", the same values in every column.
+	Dedupe    Dedupe
+	DedupeKey string
+
+	// Progress, if not nil, is called periodically by ReadAllSlice, ReadTable,
+	// ReadAllStats, ReadAllSparse, ReadAllColMajor and ReadSample with the number of bytes read, the total number of
+	// bytes, and the number of records read. The total is -1 if the size of
+	// the input is not known.
+	Progress func(bytesRead, totalBytes int64, rows int)
+
+	hasEndingComma bool
+	foundFields    bool        // FieldsPerRecord was set from the file
+	more           []io.Reader // files after the current one, set by NewMultiReader
+	fileErr        error       // error starting the next file
+	file           int         // index of the file being scanned
+	lineFile       int         // file of the last line returned by next
+	timeFile       int         // file of the last record whose times were parsed
+	rawHeadings    []string    // headings as read, before the units are stripped
+	reader         io.Reader
+	counter        *countReader // counts the bytes read from reader
+	total          int64        // size of the input, or -1
+	nextProgress   int64        // bytes read at the next call to Progress
+	bom            *bomReader   // decodes the input, reused by Reset and later files
+	scanner        *bufio.Scanner
+	scanBuf        []byte // initial buffer of the scanner, reused by Reset and later files
+	lineRead       bool   // signifier that some of the
+	scanning       bool   // the scanner has been started
+	headings       []string
+	isSetup        bool
+	cols           []column
+	width          int      // number of values returned by Read
+	dataHeadings   []string // headings of the values returned by Read
+	ints           []int64  // exact values of the integer columns in the last record
+	strs           []string // values of the string columns in the last record
+	nRead          int      // number of records returned by Read or skipped
+	line           int      // number of lines scanned
+	outIndex       []int    // index in the record of the value of each column, or -1
+	order          []int    // indices in the record of the columns selected by ColumnOrder
+	checks         []check
+	cats           []*catColumn
+	times          []*timeColumn
+	prevTimes      []*timeColumn // time columns of the previous file, set by ContinueTime
+	units          []string
+	unitConv       []*UnitConversion // conversion of each column, or nil
+	ended          bool              // an empty row ended the data
+	fieldBuf       []string          // fields of the last line
+	pending        []pendingLine     // lines read ahead by DetectStrings
+	masking        bool              // present is set by Read
+	present        []bool            // whether each value of the last record was in the file
+	offset         []float64
+	scale          []float64
 }
 
-func NewReader(r io.Reader) *Reader {
-	return &Reader{
-		Comma:   ",",
-		reader:  r,
-		scanner: bufio.NewScanner(r),
+// EmptyRowPolicy sets how Read handles a line with no fields, such as a
+// blank line or a line like ",,,,"
+type EmptyRowPolicy int
+
+const (
+	EmptyRowError EmptyRowPolicy = iota // return ErrFieldCount
+	EmptyRowSkip                        // skip the line
+	EmptyRowEnd                         // treat the line as the end of the data
+)
+
+// DuplicatePolicy sets how ReadHeading handles columns with the same heading.
+// Columns are looked up by heading using the first column with the heading.
+type DuplicatePolicy int
+
+const (
+	DuplicateAllow     DuplicatePolicy = iota // keep the headings as they are
+	DuplicateError                            // return ErrDuplicateHeading
+	DuplicateSuffix                           // rename the later columns, as value, value_2, value_3
+	DuplicateKeepFirst                        // omit the later columns from the records
+)
+
+// NewReader returns a Reader reading from r, configured by the options. A
+// UTF-8 byte-order mark at the start of r is removed, and r is decoded from
+// UTF-16 if it starts with a UTF-16 byte-order mark.
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	rd := &Reader{
+		Comma:  ",",
+		reader: r,
+	}
+	rd.counter = &countReader{r: r}
+	rd.newScanner()
+	rd.total = inputSize(r)
+	for _, opt := range opts {
+		opt(rd)
+	}
+	return rd
+}
+
+// newScanner starts a scanner of the lines read from the counter. The
+// bomReader and the buffer of the previous scanner are reused.
+func (r *Reader) newScanner() {
+	if r.bom == nil {
+		r.bom = newBOMReader(r.counter)
+	} else {
+		r.bom.reset(r.counter)
+	}
+	r.scanner = bufio.NewScanner(r.bom)
+	r.scanner.Split(scanLines)
+}
+
+// Reset discards the state of the Reader and makes it read from r, keeping
+// its configuration and reusing its buffers. FieldsPerRecord is cleared
+// unless it was preset.
+func (r *Reader) Reset(rd io.Reader) {
+	if r.foundFields {
+		r.FieldsPerRecord = 0
 	}
+	r.foundFields = false
+	r.hasEndingComma = false
+	r.reader = rd
+	r.counter.r, r.counter.n = rd, 0
+	r.newScanner()
+	r.total = inputSize(rd)
+	r.nextProgress = 0
+	r.more = nil
+	r.fileErr = nil
+	r.file = 0
+	r.lineFile = 0
+	r.timeFile = 0
+	r.rawHeadings = nil
+	r.lineRead = false
+	r.scanning = false
+	r.headings = nil
+	r.isSetup = false
+	r.cols = nil
+	r.width = 0
+	r.dataHeadings = nil
+	r.ints = nil
+	r.strs = nil
+	r.nRead = 0
+	r.line = 0
+	r.outIndex = nil
+	r.order = nil
+	r.checks = nil
+	r.cats = nil
+	r.times = nil
+	r.prevTimes = nil
+	r.units = nil
+	r.unitConv = nil
+	r.ended = false
+	r.pending = nil
+	r.offset = nil
+	r.scale = nil
 }
 
 var (
-	ErrTrailingComma = errors.New("extra delimeter at end of line")
-	ErrFieldCount    = errors.New("wrong number of fields in line")
+	ErrTrailingComma    = errors.New("extra delimeter at end of line")
+	ErrFieldCount       = errors.New("wrong number of fields in line")
+	ErrUnknownColumn    = errors.New("column not found")
+	ErrUnknownToken     = errors.New("unrecognized token")
+	ErrCheck            = errors.New("check failed")
+	ErrHeadingMismatch  = errors.New("headings do not match")
+	ErrStaleCache       = errors.New("cache does not match source file")
+	ErrRange            = errors.New("value out of range")
+	ErrMissing          = errors.New("missing value")
+	ErrDuplicateHeading = errors.New("duplicate heading")
 )
 
+// ParseError is returned by Read for a record that could not be read
+type ParseError struct {
+	Line   int // line of the file, starting at 1
+	Column int // column of the field, or -1 if the error is not specific to one field
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column < 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned by ReadAllSlice and ReadTable when they fail partway
+// through the file. It holds the records read successfully, so that the data
+// can be recovered from a mostly good file.
+type PartialError struct {
+	Data [][]float64 // records read before the error
+	Line int         // line of the file where reading stopped, starting at 1
+	Err  error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("read stopped after %d records: %v", len(e.Data), e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+func (r *Reader) partialError(alldata [][]float64, err error) error {
+	return &PartialError{Data: r.arrange(alldata), Line: r.line, Err: err}
+}
+
 // ReadHeading reads the string fields at the start, ignoring quotations if they are there
 func (r *Reader) ReadHeading() (headings []string, err error) {
+	headings, err = r.scanHeading()
+	if err != nil {
+		return nil, err
+	}
+	r.rawHeadings = append([]string(nil), headings...)
+
+	if r.FieldsPerRecord != 0 && len(headings) != r.FieldsPerRecord {
+		return nil, ErrFieldCount
+	}
+	if r.FieldsPerRecord == 0 {
+		r.FieldsPerRecord = len(headings)
+		r.foundFields = true
+	}
+	if r.ParseUnits {
+		r.units = make([]string, len(headings))
+		for i, str := range headings {
+			name, unit := SplitUnit(str)
+			r.units[i] = unit
+			if r.StripUnits {
+				headings[i] = name
+			}
+		}
+		r.findConversions()
+	}
+	if r.CleanHeadings != 0 {
+		for i, str := range headings {
+			headings[i] = CleanHeading(str, r.CleanHeadings)
+		}
+	}
+	if err := r.dedupe(headings); err != nil {
+		return nil, err
+	}
+	r.headings = headings
+	r.lineRead = true
+	return headings, nil
+}
+
+// dedupe applies the DuplicateHeadings policy to the headings
+func (r *Reader) dedupe(headings []string) error {
+	if r.DuplicateHeadings == DuplicateAllow || r.DuplicateHeadings == DuplicateKeepFirst {
+		return nil
+	}
+	seen := make(map[string]bool, len(headings))
+	for _, h := range headings {
+		seen[h] = true
+	}
+	counts := make(map[string]int, len(headings))
+	for i, h := range headings {
+		counts[h]++
+		if counts[h] == 1 {
+			continue
+		}
+		if r.DuplicateHeadings == DuplicateError {
+			return fmt.Errorf("%w: %q", ErrDuplicateHeading, h)
+		}
+		name := h
+		for n := counts[h]; seen[name]; n++ {
+			name = h + "_" + strconv.Itoa(n)
+			counts[h] = n
+		}
+		seen[name] = true
+		headings[i] = name
+	}
+	return nil
+}
+
+// scanHeading reads the first line that is not empty or a comment and
+// returns its fields with the quotations removed
+func (r *Reader) scanHeading() (headings []string, err error) {
 	// Read until prefix isn't comment
 	var line string
-	for b := r.scanner.Scan(); b; b = r.scanner.Scan() {
+	for b := r.scan(); b; b = r.scan() {
+		r.line++
 		line = r.scanner.Text()
 		if line == "" {
 			continue
@@ -54,116 +407,480 @@ func (r *Reader) ReadHeading() (headings []string, err error) {
 		}
 		break
 	}
-	if err := r.scanner.Err(); err != nil {
+	if err := r.scanErr(); err != nil {
 		return nil, err
 	}
-	comma := r.HeadingComma
-	if comma == "" {
-		comma = r.Comma
+	var strs []string
+	if r.Splitter != nil {
+		strs = r.Splitter.Split(line)
+	} else {
+		comma := r.HeadingComma
+		if comma == "" {
+			comma = r.Comma
+		}
+		if strings.Contains(line, "\"") {
+			// Quoted headings may contain the delimiter
+			strs = QuoteSplitter(comma).Split(line)
+		} else {
+			strs = strings.Split(line, comma)
+		}
 	}
-	strs := strings.Split(line, r.Comma)
 	for _, str := range strs {
+		if str == xlsxMissing {
+			// An empty cell of a worksheet keeps its column
+			headings = append(headings, "")
+			continue
+		}
 		str = strings.TrimSpace(str)
 		if len(str) != 0 {
+			str = strings.TrimSuffix(str, "\"")
+			str = strings.TrimPrefix(str, "\"")
 			headings = append(headings, str)
 		}
 	}
-
-	if r.FieldsPerRecord != 0 && len(headings) != r.FieldsPerRecord {
-		return nil, ErrFieldCount
-	}
-	r.FieldsPerRecord = len(headings)
-
-	// Remove the quotations
-	for i, str := range headings {
-		str = strings.TrimSuffix(str, "\"")
-		str = strings.TrimPrefix(str, "\"")
-		headings[i] = str
-	}
-	r.lineRead = true
 	return headings, nil
 }
 
 // Read reads a single record from the CSV. ReadHeading must be called first if
-// there are headings. Returns nil if EOF reached.
+// there are headings. Returns nil if EOF reached. Errors in the record are
+// returned as a *ParseError.
 func (r *Reader) Read() ([]float64, error) {
-	b := r.scanner.Scan()
-	if !b {
-		return nil, r.scanner.Err()
-	}
-	line := r.scanner.Text()
-	allStrs := strings.Split(line, r.Comma)
-
-	strs := make([]string, 0, len(allStrs))
-	// Eliminate fields that are only whitespace
-	for _, str := range allStrs {
-		str = strings.TrimSpace(str)
-		if len(str) != 0 {
-			strs = append(strs, str)
-		}
+	strs, ok := r.next()
+	if !ok {
+		return nil, r.scanErr()
 	}
 
 	if !r.lineRead {
 		r.lineRead = true
 		if r.FieldsPerRecord == 0 {
 			r.FieldsPerRecord = len(strs)
+			r.foundFields = true
 		}
 	}
 
 	if len(strs) != r.FieldsPerRecord {
-		return nil, ErrFieldCount
+		return nil, &ParseError{Line: r.line, Column: -1, Err: ErrFieldCount}
+	}
+
+	if !r.isSetup {
+		if err := r.setup(strs); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.lineFile != r.timeFile {
+		r.continueFiles()
+		r.timeFile = r.lineFile
 	}
 
 	// Parse all of the data
-	data := make([]float64, r.FieldsPerRecord)
-	var err error
+	data := make([]float64, 0, r.width)
+	if r.masking {
+		r.present = r.present[:0]
+	}
+	if r.AddIndex {
+		data = append(data, float64(r.IndexStart+r.nRead))
+		if r.masking {
+			r.present = append(r.present, true)
+		}
+	}
 	for i, str := range strs {
-		data[i], err = strconv.ParseFloat(str, 64)
-		if err != nil {
-			return nil, err
+		switch r.cols[i].kind {
+		case stringKind:
+			r.strs[i] = str
+		case skipKind:
+		case uncertainKind:
+			v, sigma, err := r.parseUncertain(str)
+			if err != nil {
+				return nil, &ParseError{Line: r.line, Column: i, Err: err}
+			}
+			data = append(data, r.convert(i, v), r.convertUnit(i, sigma, true))
+			if r.masking {
+				missing := r.isNaN(str)
+				r.present = append(r.present, !missing, !missing && !math.IsNaN(sigma))
+			}
+		default:
+			v, err := r.parseField(i, str)
+			if err != nil {
+				return nil, &ParseError{Line: r.line, Column: i, Err: err}
+			}
+			data = append(data, r.convert(i, v))
+			if r.masking {
+				r.present = append(r.present, !r.isNaN(str))
+			}
+		}
+	}
+	for _, c := range r.checks {
+		if err := c.check(data); err != nil {
+			return nil, &ParseError{Line: r.line, Column: -1, Err: err}
 		}
 	}
+	r.nRead++
 	return data, nil
 }
 
-// ReadAll reads all of the numeric records from the CSV. ReadHeading must be called first if
-// there are headings
-func (r *Reader) ReadAll() (*mat64.Dense, error) {
-	alldata := make([][]float64, 0)
-	count := 0
+// Skip discards the next n records without parsing them. It returns io.EOF
+// if the data ends first. Skipped records are counted by AddIndex.
+func (r *Reader) Skip(n int) error {
+	for i := 0; i < n; i++ {
+		strs, ok := r.next()
+		if !ok {
+			if err := r.scanErr(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+		if !r.lineRead {
+			r.lineRead = true
+			if r.FieldsPerRecord == 0 {
+				r.FieldsPerRecord = len(strs)
+				r.foundFields = true
+			}
+		}
+		r.nRead++
+	}
+	return nil
+}
+
+// Headings returns the headings read by ReadHeading, or nil if it has not been called
+func (r *Reader) Headings() []string {
+	return r.headings
+}
+
+// DataHeadings returns the headings of the columns of the records returned by
+// ReadAllSlice. They differ from Headings when columns are added, removed, or
+// expanded.
+func (r *Reader) DataHeadings() []string {
+	if !r.isSetup {
+		return r.headings
+	}
+	return r.matHeadings()
+}
+
+// scan scans the next line, setting the size of the scanner buffer before
+// the first line
+func (r *Reader) scan() bool {
+	if !r.scanning {
+		r.scanning = true
+		max := r.MaxLineSize
+		if max <= 0 {
+			max = bufio.MaxScanTokenSize
+		}
+		// The initial size of a bufio.Scanner buffer, but no larger than a line
+		size := 4096
+		if max < size {
+			size = max
+		}
+		if cap(r.scanBuf) != size {
+			r.scanBuf = make([]byte, size)
+		}
+		r.scanner.Buffer(r.scanBuf, max)
+	}
+	return r.scanner.Scan()
+}
+
+// scanErr returns the error of the scanner, noting the line that was too long
+func (r *Reader) scanErr() error {
+	if r.fileErr != nil {
+		return r.fileErr
+	}
+	err := r.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("line %d: %w (set Reader.MaxLineSize)", r.line+1, err)
+	}
+	return err
+}
+
+// scanLines is a bufio.SplitFunc like bufio.ScanLines, but lines may end in
+// "\n", "\r\n" or a bare "\r", in any mixture
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			// Request more data to see if the next byte is '\n'
+			return 0, nil, nil
+		}
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// next scans the next line and returns its fields. Lines without fields are
+// handled as set by EmptyRows. Returns false at the end of the data.
+func (r *Reader) next() ([]string, bool) {
+	if len(r.pending) != 0 {
+		p := r.pending[0]
+		r.pending = r.pending[1:]
+		r.line = p.line
+		r.lineFile = p.file
+		return p.fields, true
+	}
 	for {
-		data, err := r.Read()
-		if err != nil {
-			return nil, err
+		for !r.ended && r.scan() {
+			r.line++
+			strs := r.fields(r.scanner.Text())
+			r.lineFile = r.file
+			switch {
+			case len(strs) != 0 || r.EmptyRows == EmptyRowError:
+				return strs, true
+			case r.EmptyRows == EmptyRowEnd:
+				r.ended = true
+			}
 		}
-		if data == nil {
+		if !r.nextFile() {
+			return nil, false
+		}
+	}
+}
+
+// fields splits a line into its fields, eliminating fields that are only
+// whitespace. The returned slice is reused by the next call.
+func (r *Reader) fields(line string) []string {
+	strs := r.fieldBuf[:0]
+	if r.Splitter != nil || r.Comma == "" {
+		var all []string
+		if r.Splitter != nil {
+			all = r.Splitter.Split(line)
+		} else {
+			all = strings.Split(line, r.Comma)
+		}
+		for _, str := range all {
+			str = strings.TrimSpace(str)
+			if len(str) != 0 {
+				strs = append(strs, str)
+			}
+		}
+		r.fieldBuf = strs
+		return strs
+	}
+	// Split at Comma without allocating the substrings
+	for len(line) > 0 {
+		str := line
+		if i := strings.Index(line, r.Comma); i >= 0 {
+			str, line = line[:i], line[i+len(r.Comma):]
+		} else {
+			line = ""
+		}
+		str = strings.TrimSpace(str)
+		if len(str) != 0 {
+			strs = append(strs, str)
+		}
+	}
+	r.fieldBuf = strs
+	return strs
+}
+
+// ReadAllSlice reads all of the numeric records from the CSV. ReadHeading must be called first if
+// there are headings. If a record cannot be read, the returned error is a
+// *PartialError holding the records read before it. The gonumcsv package
+// reads the records into a matrix.
+func (r *Reader) ReadAllSlice() ([][]float64, error) {
+	alldata, _, err := r.readRecords(nil, false)
+	if err != nil {
+		return nil, r.partialError(alldata, err)
+	}
+	return r.arrange(alldata), nil
+}
+
+// readRecords reads all of the remaining records and imputes their missing
+// values. each, if not nil, is called with each record that is kept and its
+// index in alldata. The index is that of an earlier record if it is replaced
+// by a duplicate. If wantStats is set, the statistics of the records before
+// they are imputed are returned. If there is an error, the records read
+// before it are returned.
+func (r *Reader) readRecords(each func(i int, data []float64), wantStats bool) (alldata [][]float64, stats *Stats, err error) {
+	alldata = make([][]float64, 0)
+	var imp *imputer
+	var dd *deduper
+	replaced := false // a record was replaced by a later duplicate
+	for r.MaxRows <= 0 || len(alldata) < r.MaxRows {
+		var data []float64
+		data, err = r.Read()
+		if err != nil || data == nil {
 			break
 		}
+		r.progress(false)
+		if r.RowFilter != nil && !r.RowFilter(data) {
+			continue
+		}
+		if r.Impute == ImputeDrop && hasNaN(data) {
+			continue
+		}
+		if r.Dedupe != DedupeNone {
+			if dd == nil {
+				if dd, err = r.newDeduper(); err != nil {
+					break
+				}
+			}
+			if i, dup := dd.find(data, len(alldata)); dup {
+				if r.Dedupe == DedupeLast {
+					alldata[i] = data
+					replaced = true
+					if each != nil {
+						each(i, data)
+					}
+				}
+				continue
+			}
+		}
+		if r.Impute != ImputeNone && r.Impute != ImputeDrop {
+			if imp == nil {
+				imp = r.newImputer(len(data))
+			}
+			imp.add(data)
+		}
+		if wantStats || r.Normalize != NormalizeNone {
+			if stats == nil {
+				stats = newStats(len(data))
+			}
+			stats.add(data)
+		}
 		alldata = append(alldata, data)
-		count++
+		if each != nil {
+			each(len(alldata)-1, data)
+		}
+	}
+	r.progress(true)
+	if replaced {
+		// The statistics were accumulated from the replaced records
+		stats, imp = r.restat(alldata, stats != nil, imp != nil)
+	}
+	if stats != nil {
+		stats.finish()
+	}
+	if imp != nil {
+		imp.fill(alldata, r.ImputeValue)
+	}
+	if stats != nil && r.Normalize != NormalizeNone {
+		r.normalize(alldata, stats)
+	}
+	return alldata, stats, err
+}
+
+// restat recomputes the statistics and the imputer from the records, for
+// when records were replaced as they were read
+func (r *Reader) restat(alldata [][]float64, wantStats, wantImp bool) (stats *Stats, imp *imputer) {
+	if wantStats {
+		stats = newStats(len(alldata[0]))
+	}
+	if wantImp {
+		imp = r.newImputer(len(alldata[0]))
+	}
+	for _, data := range alldata {
+		if stats != nil {
+			stats.add(data)
+		}
+		if imp != nil {
+			imp.add(data)
+		}
+	}
+	return stats, imp
+}
+
+// arrange selects the columns of the records given by ColumnOrder, expands the
+// one-hot columns, and transposes the records if Transpose is set
+func (r *Reader) arrange(alldata [][]float64) [][]float64 {
+	return r.rearrange(alldata, false)
+}
+
+// rearrange arranges the records as in arrange. If mask is set, the records
+// are masks of 0 and 1, and the mask of a one-hot column is copied to each of
+// its columns.
+func (r *Reader) rearrange(alldata [][]float64, mask bool) [][]float64 {
+	span := r.oneHotSpans()
+	if span == nil && r.order == nil && !r.Transpose {
+		return alldata
+	}
+	cols := r.outColumns()
+	c := 0
+	for _, j := range cols {
+		if span != nil && span[j] > 0 {
+			c += span[j]
+		} else {
+			c++
+		}
+	}
+	n := len(alldata)
+	raw := make([]float64, n*c)
+	set := func(i, k int, v float64) {
+		raw[i*c+k] = v
+	}
+	if r.Transpose {
+		set = func(i, k int, v float64) {
+			raw[k*n+i] = v
+		}
 	}
-	mat := mat64.NewDense(len(alldata), r.FieldsPerRecord, nil)
 	for i, record := range alldata {
-		for j, v := range record {
-			mat.Set(i, j, v)
+		k := 0
+		for _, j := range cols {
+			v := record[j]
+			if span == nil || span[j] == 0 {
+				set(i, k, v)
+				k++
+				continue
+			}
+			if mask {
+				for m := 0; m < span[j]; m++ {
+					set(i, k+m, v)
+				}
+			} else if !math.IsNaN(v) {
+				// A missing category leaves every column of the value 0
+				set(i, k+int(v), 1)
+			}
+			k += span[j]
 		}
 	}
-	return mat, nil
+	if r.Transpose {
+		n, c = c, n
+	}
+	rows := make([][]float64, n)
+	for i := range rows {
+		rows[i] = raw[i*c : (i+1)*c]
+	}
+	return rows
 }
 
 type Writer struct {
 	Comma        string
 	UseCRLF      bool
 	QuoteHeading bool // Put quotes around heading strings
-	FloatFmt     byte
-	w            *bufio.Writer
+	FloatFmt     byte // format of the values as in strconv.FormatFloat (set to 'e' by NewWriter)
+	Precision    int  // precision of the values as in strconv.FormatFloat (set to 16 by NewWriter)
+
+	// ColumnFormats overrides FloatFmt and Precision for the columns with the
+	// given indices
+	ColumnFormats map[int]Format
+	w             *bufio.Writer
+}
+
+// Format is the format and precision of a float as in strconv.FormatFloat
+type Format struct {
+	Fmt       byte
+	Precision int
 }
 
 func NewWriter(w io.Writer) *Writer {
 	return &Writer{
-		Comma:    ",",
-		w:        bufio.NewWriter(w),
-		FloatFmt: 'e',
+		Comma:     ",",
+		w:         bufio.NewWriter(w),
+		FloatFmt:  'e',
+		Precision: 16,
 	}
 }
 
@@ -181,12 +898,7 @@ func (w *Writer) WriteHeading(heading []string) (err error) {
 			return
 		}
 	}
-	if w.UseCRLF {
-		_, err = w.w.WriteString("\r\n")
-	} else {
-		err = w.w.WriteByte('\n')
-	}
-	return err
+	return w.newline()
 }
 
 func (w *Writer) Write(record []float64) error {
@@ -196,32 +908,43 @@ func (w *Writer) Write(record []float64) error {
 				return err
 			}
 		}
-		str := strconv.FormatFloat(field, w.FloatFmt, 16, 64)
+		f := Format{Fmt: w.FloatFmt, Precision: w.Precision}
+		if cf, ok := w.ColumnFormats[n]; ok {
+			f = cf
+		}
+		str := strconv.FormatFloat(field, f.Fmt, f.Precision, 64)
 		if _, err := w.w.WriteString(str); err != nil {
 			return err
 		}
 	}
-	var err error
+	return w.newline()
+}
+
+func (w *Writer) newline() error {
 	if w.UseCRLF {
-		_, err = w.w.WriteString("\r\n")
-	} else {
-		err = w.w.WriteByte('\n')
+		_, err := w.w.WriteString("\r\n")
+		return err
 	}
-	return err
+	return w.w.WriteByte('\n')
 }
 
-func (w *Writer) WriteAll(headings []string, data *mat64.Dense) error {
+// WriteAllSlice writes the headings, if not nil, and then the records, and
+// flushes the output
+func (w *Writer) WriteAllSlice(headings []string, data [][]float64) error {
 	if headings != nil {
 		if err := w.WriteHeading(headings); err != nil {
 			return err
 		}
 	}
-	r, _ := data.Dims()
-	for i := 0; i < r; i++ {
-		err := w.Write(data.RawRowView(i))
-		if err != nil {
+	for _, record := range data {
+		if err := w.Write(record); err != nil {
 			return err
 		}
 	}
 	return w.w.Flush()
 }
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,355 @@
+package numcsv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReset(t *testing.T) {
+	r := NewReader(bytes.NewReader(encodeUTF16("a\n1\n", false)))
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil || !reflect.DeepEqual(headings, []string{"a"}) || !reflect.DeepEqual(data, [][]float64{{1}}) {
+		t.Fatalf("got %q %v %v", headings, data, err)
+	}
+	r.Reset(strings.NewReader("\xEF\xBB\xBFb,c\n2,3\n"))
+	headings, data, err = r.ReadAllWithHeading()
+	if err != nil || !reflect.DeepEqual(headings, []string{"b", "c"}) || !reflect.DeepEqual(data, [][]float64{{2, 3}}) {
+		t.Fatalf("after Reset: got %q %v %v", headings, data, err)
+	}
+
+	// Reset reuses the buffers, allocating only the new scanner
+	src := strings.NewReader("")
+	allocs := testing.AllocsPerRun(100, func() {
+		src.Reset("d\n4\n")
+		r.Reset(src)
+	})
+	if allocs > 1 {
+		t.Errorf("Reset allocates %v times, want at most 1", allocs)
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	const input = "a\rb\r\nc\n\rd\r\r\ne\r"
+	want := []string{"a", "b", "c", "", "d", "", "e"}
+	// Reading one byte at a time puts each \r at the end of the buffer
+	for _, rd := range []io.Reader{strings.NewReader(input), iotest.OneByteReader(strings.NewReader(input))} {
+		s := bufio.NewScanner(rd)
+		s.Split(scanLines)
+		var got []string
+		for s.Scan() {
+			got = append(got, s.Text())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("lines = %q, want %q", got, want)
+		}
+	}
+
+	r := NewReader(iotest.OneByteReader(strings.NewReader("a,b\r1,2\r\n3,4\n5,6\r")))
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(headings, []string{"a", "b"}) || !reflect.DeepEqual(data, [][]float64{{1, 2}, {3, 4}, {5, 6}}) {
+		t.Errorf("got %q, %v", headings, data)
+	}
+}
+
+func TestMaxLineSize(t *testing.T) {
+	long := strings.Repeat("0", bufio.MaxScanTokenSize) + "1"
+	r := NewReader(strings.NewReader("1\n2\n" + long + "\n"))
+	r.NoHeading = true
+	_, err := r.ReadAllSlice()
+	if !errors.Is(err, bufio.ErrTooLong) || !strings.Contains(err.Error(), "line 3") || !strings.Contains(err.Error(), "MaxLineSize") {
+		t.Errorf("err = %v, want ErrTooLong on line 3 mentioning MaxLineSize", err)
+	}
+
+	r = NewReader(strings.NewReader(long + "\n" + long + "\n"))
+	r.NoHeading = true
+	r.MaxLineSize = len(long) + 1
+	data, err := r.ReadAllSlice()
+	if err != nil || !reflect.DeepEqual(data, [][]float64{{1}, {1}}) {
+		t.Errorf("MaxLineSize set: data = %v, err = %v", data, err)
+	}
+
+	r = NewReader(strings.NewReader("1,2,3\n"))
+	r.NoHeading = true
+	r.MaxLineSize = 4
+	if _, err := r.ReadAllSlice(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("small MaxLineSize: err = %v, want ErrTooLong", err)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	r := NewReader(strings.NewReader("a\n1\nbad\n3\n4\n"))
+	r.AddIndex = true
+	r.ReadHeading()
+	// The skipped records are not parsed
+	if err := r.Skip(2); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The index counts the skipped records
+	if want := [][]float64{{2, 3}, {3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	if err := r.Skip(0); err != nil {
+		t.Errorf("Skip(0) at end: err = %v", err)
+	}
+	if err := r.Skip(1); err != io.EOF {
+		t.Errorf("Skip at end: err = %v, want io.EOF", err)
+	}
+
+	r = NewReader(strings.NewReader("1\n2\n"))
+	r.NoHeading = true
+	if err := r.Skip(3); err != io.EOF {
+		t.Errorf("Skip past end: err = %v, want io.EOF", err)
+	}
+}
+
+func TestPartialError(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n1,2\n3,4\n5,x\n7,8\n"))
+	r.ColumnOrder = []string{"b"}
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if data != nil {
+		t.Errorf("data = %v, want nil", data)
+	}
+	var perr *PartialError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v, want a *PartialError", err)
+	}
+	// The records are arranged as they would have been returned
+	if want := [][]float64{{2}, {4}}; !reflect.DeepEqual(perr.Data, want) {
+		t.Errorf("Data = %v, want %v", perr.Data, want)
+	}
+	if perr.Line != 4 {
+		t.Errorf("Line = %d, want 4", perr.Line)
+	}
+	var parse *ParseError
+	if !errors.As(err, &parse) || parse.Line != 4 || parse.Column != 1 {
+		t.Errorf("err = %v, want a ParseError at line 4, column 1", err)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b,c\n1,2,3\n4,5,6\n"))
+	r.Transpose = true
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1, 4}, {2, 5}, {3, 6}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+
+	// The columns are selected and expanded before transposing
+	r = NewReader(strings.NewReader("c,a,b\nx,1,2\ny,3,4\n"))
+	r.Transpose = true
+	r.ColumnOrder = []string{"b", "c"}
+	r.CategoricalColumns = []CategoricalColumn{{Name: "c", OneHot: true}}
+	r.ReadHeading()
+	data, err = r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{2, 4}, {1, 0}, {0, 1}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+
+	r = NewReader(strings.NewReader("a,b\n"))
+	r.Transpose = true
+	r.ReadHeading()
+	// The transpose has a row for each column, with no values
+	if data, err := r.ReadAllSlice(); err != nil || !reflect.DeepEqual(data, [][]float64{{}, {}}) {
+		t.Errorf("no records: data = %v, err = %v", data, err)
+	}
+}
+
+func TestEmptyRows(t *testing.T) {
+	const input = "a,b\n1,2\n\n3,4\n , \n5,6\n"
+	for _, test := range []struct {
+		policy EmptyRowPolicy
+		want   [][]float64
+		line   int // line of the error, or 0 if none
+	}{
+		{EmptyRowError, nil, 3},
+		{EmptyRowSkip, [][]float64{{1, 2}, {3, 4}, {5, 6}}, 0},
+		{EmptyRowEnd, [][]float64{{1, 2}}, 0},
+	} {
+		r := NewReader(strings.NewReader(input))
+		r.EmptyRows = test.policy
+		r.ReadHeading()
+		data, err := r.ReadAllSlice()
+		if test.line != 0 {
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Line != test.line || !errors.Is(err, ErrFieldCount) {
+				t.Errorf("policy %d: err = %v, want ErrFieldCount on line %d", test.policy, err, test.line)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(data, test.want) {
+			t.Errorf("policy %d: data = %v, err = %v, want %v", test.policy, data, err, test.want)
+		}
+	}
+
+	// A line of only delimiters also ends the data
+	r := NewReader(strings.NewReader("a,b\n1,2\n,\n3,4\n"))
+	r.EmptyRows = EmptyRowEnd
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if err != nil || !reflect.DeepEqual(data, [][]float64{{1, 2}}) {
+		t.Errorf("delimiters: data = %v, err = %v", data, err)
+	}
+}
+
+func TestWriterFormat(t *testing.T) {
+	data := [][]float64{{1.5, 2, 1.0 / 3}}
+	for _, test := range []struct {
+		name   string
+		fmt    byte
+		prec   int
+		cols   map[int]Format
+		output string
+	}{
+		{
+			name:   "default",
+			fmt:    'e',
+			prec:   16,
+			output: "a,b,c\n1.5000000000000000e+00,2.0000000000000000e+00,3.3333333333333331e-01\n",
+		},
+		{
+			name:   "precision",
+			fmt:    'f',
+			prec:   2,
+			output: "a,b,c\n1.50,2.00,0.33\n",
+		},
+		{
+			name:   "shortest",
+			fmt:    'g',
+			prec:   -1,
+			output: "a,b,c\n1.5,2,0.3333333333333333\n",
+		},
+		{
+			name:   "column formats",
+			fmt:    'g',
+			prec:   -1,
+			cols:   map[int]Format{1: {Fmt: 'f', Precision: 0}, 2: {Fmt: 'e', Precision: 3}},
+			output: "a,b,c\n1.5,2,3.333e-01\n",
+		},
+		{
+			// A format for a column past the end of the record is unused
+			name:   "column out of range",
+			fmt:    'f',
+			prec:   1,
+			cols:   map[int]Format{5: {Fmt: 'e', Precision: 3}},
+			output: "a,b,c\n1.5,2.0,0.3\n",
+		},
+	} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		w.FloatFmt = test.fmt
+		w.Precision = test.prec
+		w.ColumnFormats = test.cols
+		if err := w.WriteAllSlice([]string{"a", "b", "c"}, data); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.output {
+			t.Errorf("%s: output = %q, want %q", test.name, got, test.output)
+		}
+	}
+}
+
+func TestDuplicateHeadings(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		policy   DuplicatePolicy
+		input    string
+		headings []string
+		data     [][]float64
+		err      error
+	}{
+		{
+			name:     "allow",
+			policy:   DuplicateAllow,
+			input:    "a,b,a\n1,2,3\n",
+			headings: []string{"a", "b", "a"},
+			data:     [][]float64{{1, 2, 3}},
+		},
+		{
+			name:   "error",
+			policy: DuplicateError,
+			input:  "a,b,a\n1,2,3\n",
+			err:    ErrDuplicateHeading,
+		},
+		{
+			name:     "error without duplicates",
+			policy:   DuplicateError,
+			input:    "a,b\n1,2\n",
+			headings: []string{"a", "b"},
+			data:     [][]float64{{1, 2}},
+		},
+		{
+			name:     "suffix",
+			policy:   DuplicateSuffix,
+			input:    "a,b,a,a\n1,2,3,4\n",
+			headings: []string{"a", "b", "a_2", "a_3"},
+			data:     [][]float64{{1, 2, 3, 4}},
+		},
+		{
+			// a_2 is already taken, so the repeated a becomes a_3
+			name:     "suffix collision",
+			policy:   DuplicateSuffix,
+			input:    "a,a_2,a\n1,2,3\n",
+			headings: []string{"a", "a_2", "a_3"},
+			data:     [][]float64{{1, 2, 3}},
+		},
+		{
+			name:     "suffix collision later",
+			policy:   DuplicateSuffix,
+			input:    "a,a,a_2\n1,2,3\n",
+			headings: []string{"a", "a_3", "a_2"},
+			data:     [][]float64{{1, 2, 3}},
+		},
+		{
+			name:     "keep first",
+			policy:   DuplicateKeepFirst,
+			input:    "a,b,a,b\n1,2,3,4\n",
+			headings: []string{"a", "b"},
+			data:     [][]float64{{1, 2}},
+		},
+	} {
+		r := NewReader(strings.NewReader(test.input))
+		r.DuplicateHeadings = test.policy
+		_, err := r.ReadHeading()
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error = %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		data, err := r.ReadAllSlice()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got := r.DataHeadings(); !reflect.DeepEqual(got, test.headings) {
+			t.Errorf("%s: headings = %q, want %q", test.name, got, test.headings)
+		}
+		if !reflect.DeepEqual(data, test.data) {
+			t.Errorf("%s: data = %v, want %v", test.name, data, test.data)
+		}
+	}
+}
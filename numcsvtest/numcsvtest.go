@@ -0,0 +1,139 @@
+// package numcsvtest provides helpers for testing code which uses numcsv. They
+// check that files are read the same way as numcsv changes, and that records
+// survive being written and read again.
+
+package numcsvtest
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/btracey/numcsv"
+)
+
+// Read reads all of the records of data using a Reader set up by configure,
+// which may be nil. The heading is read unless configure sets NoHeading. The
+// returned headings are those of the columns of the records, as returned by
+// Reader.DataHeadings.
+func Read(data []byte, configure func(*numcsv.Reader)) (headings []string, records [][]float64, err error) {
+	r := numcsv.NewReader(bytes.NewReader(data))
+	if configure != nil {
+		configure(r)
+	}
+	if !r.NoHeading {
+		if _, err = r.ReadHeading(); err != nil {
+			return nil, nil, err
+		}
+	}
+	records, err = r.ReadAllSlice()
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.DataHeadings(), records, nil
+}
+
+// Write writes the headings and records at full precision
+func Write(headings []string, records [][]float64) ([]byte, error) {
+	var b bytes.Buffer
+	w := numcsv.NewWriter(&b)
+	w.FloatFmt, w.Precision = 'g', -1
+	if err := w.WriteAllSlice(headings, records); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// readWritten reads records written by Write. The columns of the records
+// are plain numbers, so they are read without the configuration that
+// produced them, which may add columns such as the index a second time.
+func readWritten(data []byte, headings []string) ([]string, [][]float64, error) {
+	return Read(data, func(r *numcsv.Reader) { r.NoHeading = headings == nil })
+}
+
+// EqualRecords returns whether a and b have the same shape and their values
+// are within tol of each other. NaN values are equal to each other.
+func EqualRecords(a, b [][]float64, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j, v := range a[i] {
+			w := b[i][j]
+			if math.IsNaN(v) && math.IsNaN(w) {
+				continue
+			}
+			if v != w && !(math.Abs(v-w) <= tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RoundTrip checks that the records read from data using configure are the
+// same, to within tol, after they are written and read again. The written
+// records are read as plain numeric columns, without configure.
+func RoundTrip(t testing.TB, data []byte, configure func(*numcsv.Reader), tol float64) {
+	t.Helper()
+	headings, records, err := Read(data, configure)
+	if err != nil {
+		t.Fatalf("numcsvtest: reading input: %v", err)
+	}
+	written, err := Write(headings, records)
+	if err != nil {
+		t.Fatalf("numcsvtest: writing records: %v", err)
+	}
+	_, again, err := readWritten(written, headings)
+	if err != nil {
+		t.Fatalf("numcsvtest: reading written records: %v", err)
+	}
+	if !EqualRecords(records, again, tol) {
+		t.Errorf("numcsvtest: records changed after writing and reading")
+	}
+}
+
+// Golden checks that the records read from data are the same, to within tol,
+// as the records in the golden file at path. If update is true, the golden
+// file is written instead.
+func Golden(t testing.TB, path string, data []byte, configure func(*numcsv.Reader), tol float64, update bool) {
+	t.Helper()
+	headings, records, err := Read(data, configure)
+	if err != nil {
+		t.Fatalf("numcsvtest: reading input: %v", err)
+	}
+	if update {
+		written, err := Write(headings, records)
+		if err != nil {
+			t.Fatalf("numcsvtest: writing records: %v", err)
+		}
+		if err := os.WriteFile(path, written, 0666); err != nil {
+			t.Fatalf("numcsvtest: writing golden file: %v", err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("numcsvtest: reading golden file: %v", err)
+	}
+	wantHeadings, want, err := readWritten(golden, headings)
+	if err != nil {
+		t.Fatalf("numcsvtest: reading golden file: %v", err)
+	}
+	if len(wantHeadings) != len(headings) {
+		t.Errorf("numcsvtest: headings %v, golden file has %v", headings, wantHeadings)
+	}
+	for i := range headings {
+		if i < len(wantHeadings) && headings[i] != wantHeadings[i] {
+			t.Errorf("numcsvtest: headings %v, golden file has %v", headings, wantHeadings)
+			break
+		}
+	}
+	if !EqualRecords(records, want, tol) {
+		t.Errorf("numcsvtest: records differ from golden file %s", path)
+	}
+}
@@ -0,0 +1,33 @@
+package numcsvtest
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/btracey/numcsv"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte("a,s,b\n1,x,0.1\n2,y,NaN\n")
+	configure := func(r *numcsv.Reader) {
+		r.AddIndex = true
+		r.StringColumns = []numcsv.Column{{Name: "s"}}
+	}
+	headings, _, err := Read(data, configure)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"index", "a", "b"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	RoundTrip(t, data, configure, 0)
+	RoundTrip(t, []byte("1,2\n3,4\n"), func(r *numcsv.Reader) { r.NoHeading = true }, 0)
+}
+
+func TestGolden(t *testing.T) {
+	data := []byte("a, b\n1, 0.1\n2,NaN\n")
+	path := filepath.Join(t.TempDir(), "golden.csv")
+	Golden(t, path, data, nil, 0, true)
+	Golden(t, path, data, nil, 0, false)
+}
@@ -0,0 +1,86 @@
+package numcsv
+
+// Option configures a Reader, as an alternative to setting its fields
+type Option func(*Reader)
+
+// WithComma sets the field delimiter. A QuoteSplitter, as set by
+// DialectExcel, is changed to split at comma, and a CommaSplitter or
+// WhitespaceSplitter is removed so that the lines are split at comma.
+func WithComma(comma string) Option {
+	return func(r *Reader) {
+		r.Comma = comma
+		switch r.Splitter.(type) {
+		case QuoteSplitter:
+			r.Splitter = QuoteSplitter(comma)
+		case CommaSplitter, WhitespaceSplitter:
+			r.Splitter = nil
+		}
+	}
+}
+
+// WithHeadingComma sets the delimiter of the headings
+func WithHeadingComma(comma string) Option {
+	return func(r *Reader) { r.HeadingComma = comma }
+}
+
+// WithSplitter sets the Splitter of the lines
+func WithSplitter(s Splitter) Option {
+	return func(r *Reader) { r.Splitter = s }
+}
+
+// WithComment sets the prefix of comment lines
+func WithComment(comment string) Option {
+	return func(r *Reader) { r.Comment = comment }
+}
+
+// WithNaNTokens sets the tokens read as missing values
+func WithNaNTokens(tokens ...string) Option {
+	return func(r *Reader) { r.NaNTokens = tokens }
+}
+
+// WithNoHeading sets that the file has no heading line
+func WithNoHeading() Option {
+	return func(r *Reader) { r.NoHeading = true }
+}
+
+// WithUnits parses the units from the headings, removing them from the names
+func WithUnits() Option {
+	return func(r *Reader) { r.ParseUnits, r.StripUnits = true, true }
+}
+
+// WithEmptyRows sets the handling of lines without fields
+func WithEmptyRows(p EmptyRowPolicy) Option {
+	return func(r *Reader) { r.EmptyRows = p }
+}
+
+// excelErrors are the error values written by spreadsheets
+var excelErrors = []string{"#N/A", "#NA", "#DIV/0!", "#VALUE!", "#NUM!", "#REF!", "#NULL!"}
+
+// Dialects are Options for common styles of file. Options given after a
+// dialect override it.
+var (
+	// DialectStrict rejects empty lines and duplicate headings
+	DialectStrict Option = func(r *Reader) {
+		r.EmptyRows = EmptyRowError
+		r.DuplicateHeadings = DuplicateError
+	}
+
+	// DialectExcel reads files saved by spreadsheets, with quoted fields,
+	// blank lines, error values such as #N/A, and repeated headings.
+	DialectExcel Option = func(r *Reader) {
+		r.Splitter = QuoteSplitter(",")
+		r.EmptyRows = EmptyRowSkip
+		r.NaNTokens = excelErrors
+		r.DuplicateHeadings = DuplicateSuffix
+		r.CleanHeadings = CleanQuotes
+	}
+
+	// DialectWhitespace reads columns separated by spaces or tabs, with
+	// comments starting with "#", as written by many instruments and
+	// numerical codes.
+	DialectWhitespace Option = func(r *Reader) {
+		r.Splitter = WhitespaceSplitter{}
+		r.Comment = "#"
+		r.EmptyRows = EmptyRowSkip
+	}
+)
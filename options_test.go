@@ -0,0 +1,32 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Options given after a dialect override it
+func TestDialectComma(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		opts     []Option
+		headings []string
+		data     [][]float64
+	}{
+		{"excel", "a;\"b;c\"\n1;2\n", []Option{DialectExcel, WithComma(";")}, []string{"a", "b;c"}, [][]float64{{1, 2}}},
+		{"whitespace", "# x\na,b\n1,2\n", []Option{DialectWhitespace, WithComma(",")}, []string{"a", "b"}, [][]float64{{1, 2}}},
+		{"comma splitter", "a|b\n1|2\n", []Option{WithSplitter(CommaSplitter(",")), WithComma("|")}, []string{"a", "b"}, [][]float64{{1, 2}}},
+	} {
+		r := NewReader(strings.NewReader(test.input), test.opts...)
+		headings, data, err := r.ReadAllWithHeading()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(headings, test.headings) || !reflect.DeepEqual(data, test.data) {
+			t.Errorf("%s: got %q %v, want %q %v", test.name, headings, data, test.headings, test.data)
+		}
+	}
+}
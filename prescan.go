@@ -0,0 +1,23 @@
+package numcsv
+
+// Prescan reads the rest of the CSV, checking only that every line has the
+// same number of fields. The fields are not parsed. It returns the line
+// numbers, starting at 1, of the lines with the wrong number of fields. The
+// expected number of fields is FieldsPerRecord if it is set (for example by
+// ReadHeading), and otherwise the number of fields in the first line.
+func (r *Reader) Prescan() (bad []int, err error) {
+	n := r.FieldsPerRecord
+	for {
+		strs, ok := r.next()
+		if !ok {
+			break
+		}
+		if n == 0 {
+			n = len(strs)
+		}
+		if len(strs) != n {
+			bad = append(bad, r.line)
+		}
+	}
+	return bad, r.scanErr()
+}
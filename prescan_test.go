@@ -0,0 +1,31 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrescan(t *testing.T) {
+	// The fields are not parsed, so x is not an error
+	r := NewReader(strings.NewReader("a,b\n1,2\n3\nx,4\n5,6,7\n"))
+	r.ReadHeading()
+	bad, err := r.Prescan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 5}; !reflect.DeepEqual(bad, want) {
+		t.Errorf("bad = %v, want %v", bad, want)
+	}
+
+	// Without headings the first line sets the number of fields
+	r = NewReader(strings.NewReader("1,2,3\n4,5\n6,7,8\n"))
+	r.NoHeading = true
+	bad, err = r.Prescan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2}; !reflect.DeepEqual(bad, want) {
+		t.Errorf("no heading: bad = %v, want %v", bad, want)
+	}
+}
@@ -0,0 +1,50 @@
+package numcsv
+
+import (
+	"io"
+	"os"
+)
+
+// progressStep is the number of bytes read between calls to Progress
+const progressStep = 1 << 20
+
+// countReader counts the bytes read from the input
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// inputSize returns the number of bytes in r, or -1 if it is not known
+func inputSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		return info.Size()
+	}
+	return -1
+}
+
+// progress calls Progress if at least progressStep bytes have been read since
+// it was last called, or if final is set
+func (r *Reader) progress(final bool) {
+	if r.Progress == nil {
+		return
+	}
+	n := r.counter.n
+	if !final && n < r.nextProgress {
+		return
+	}
+	r.nextProgress = n + progressStep
+	r.Progress(n, r.total, r.nRead)
+}
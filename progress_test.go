@@ -0,0 +1,40 @@
+package numcsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	const rows = 1 << 18 // about 3 progressSteps of input
+	input := "a,b\n" + strings.Repeat("1.25,2.5\n", rows)
+	for _, test := range []struct {
+		name string
+		read func(r *Reader) error
+	}{
+		{"ReadAllSlice", func(r *Reader) error { _, err := r.ReadAllSlice(); return err }},
+		{"ReadAllColMajor", func(r *Reader) error { _, err := r.ReadAllColMajor(); return err }},
+		{"ReadAllSparse", func(r *Reader) error { _, err := r.ReadAllSparse(); return err }},
+		{"ReadSample", func(r *Reader) error { _, err := r.ReadSample(10, 1); return err }},
+	} {
+		r := NewReader(strings.NewReader(input))
+		// No record is kept, but progress is still reported
+		r.RowFilter = func([]float64) bool { return false }
+		var calls int
+		var last [3]int64
+		r.Progress = func(bytesRead, totalBytes int64, n int) {
+			calls++
+			last = [3]int64{bytesRead, totalBytes, int64(n)}
+		}
+		r.ReadHeading()
+		if err := test.read(r); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if calls < 3 {
+			t.Errorf("%s: %d calls to Progress, want at least 3", test.name, calls)
+		}
+		if want := [3]int64{int64(len(input)), int64(len(input)), rows}; last != want {
+			t.Errorf("%s: last call %v, want %v", test.name, last, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package numcsv
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// ReadSample reads the rest of the CSV and returns n of its records chosen
+// uniformly at random, in the order they appear in the file, keeping only n
+// records in memory. If there are fewer than n records, all are returned. The
+// records are selected and expanded as in ReadAllSlice, and RowFilter is
+// applied, but missing values are not imputed and the records are not
+// normalized. Errors are returned as in ReadAllSlice, and n must not be
+// negative.
+func (r *Reader) ReadSample(n int, seed int64) ([][]float64, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative sample size %d", n)
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	// The sample grows as the records are read, as there may be fewer than n
+	hint := n
+	if hint > 1<<16 {
+		hint = 1 << 16
+	}
+	sample := make([][]float64, 0, hint)
+	index := make([]int, 0, hint) // index of each sampled record
+	seen := 0
+	var err error
+	for n > 0 {
+		var data []float64
+		data, err = r.Read()
+		if err != nil || data == nil {
+			break
+		}
+		r.progress(false)
+		if r.RowFilter != nil && !r.RowFilter(data) {
+			continue
+		}
+		seen++
+		if len(sample) < n {
+			sample = append(sample, data)
+			index = append(index, seen)
+			continue
+		}
+		if k := rnd.Intn(seen); k < n {
+			sample[k] = data
+			index[k] = seen
+		}
+	}
+	r.progress(true)
+	sort.Sort(byIndex{index, sample})
+	if err != nil {
+		return nil, r.partialError(sample, err)
+	}
+	return r.arrange(sample), nil
+}
+
+type byIndex struct {
+	index  []int
+	sample [][]float64
+}
+
+func (b byIndex) Len() int           { return len(b.index) }
+func (b byIndex) Less(i, j int) bool { return b.index[i] < b.index[j] }
+func (b byIndex) Swap(i, j int) {
+	b.index[i], b.index[j] = b.index[j], b.index[i]
+	b.sample[i], b.sample[j] = b.sample[j], b.sample[i]
+}
@@ -0,0 +1,44 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadSample(t *testing.T) {
+	input := "i\n0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	read := func(n int, seed int64) [][]float64 {
+		r := NewReader(strings.NewReader(input))
+		r.ReadHeading()
+		sample, err := r.ReadSample(n, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sample
+	}
+	sample := read(4, 1)
+	if len(sample) != 4 {
+		t.Fatalf("sample = %v, want 4 records", sample)
+	}
+	for i := 1; i < len(sample); i++ {
+		if sample[i][0] <= sample[i-1][0] {
+			t.Errorf("sample = %v, not in file order", sample)
+		}
+	}
+	if again := read(4, 1); !reflect.DeepEqual(again, sample) {
+		t.Errorf("same seed: sample = %v, want %v", again, sample)
+	}
+	if all := read(1<<30, 1); len(all) != 10 {
+		t.Errorf("large n: %d records, want 10", len(all))
+	}
+	if none := read(0, 1); len(none) != 0 {
+		t.Errorf("n = 0: sample = %v", none)
+	}
+
+	r := NewReader(strings.NewReader(input))
+	r.ReadHeading()
+	if _, err := r.ReadSample(-1, 1); err == nil {
+		t.Errorf("n = -1: no error")
+	}
+}
@@ -0,0 +1,137 @@
+package numcsv
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// Schema declares the columns a CSV must have and the values they may hold.
+// Reader.Validate reads a CSV and reports where it does not match.
+type Schema struct {
+	Required []string // headings of columns that must be present
+	Columns  []ColumnRule
+}
+
+// ColumnRule constrains the values of a column
+type ColumnRule struct {
+	Name   string // heading of the column. If "", Index is used instead
+	Index  int    // index of the column in the record
+	Min    float64
+	Max    float64
+	HasMin bool // values less than Min are violations
+	HasMax bool // values greater than Max are violations
+	NotNaN bool // missing values are violations
+}
+
+// Violation is a value that does not satisfy a ColumnRule. Err is ErrRange or
+// ErrMissing.
+type Violation struct {
+	Line   int
+	Column string // heading of the column, or its index if there are no headings
+	Value  float64
+	Err    error
+}
+
+// Report is the result of Reader.Validate
+type Report struct {
+	Records    int           // number of records read
+	Missing    []string      // required columns, and columns of rules, that are not present
+	Errors     []*ParseError // records that could not be read
+	Violations []Violation
+}
+
+// OK returns whether the CSV matched the schema
+func (rep *Report) OK() bool {
+	return len(rep.Missing) == 0 && len(rep.Errors) == 0 && len(rep.Violations) == 0
+}
+
+type rule struct {
+	ColumnRule
+	name string
+	idx  int // index of the column in the record
+}
+
+// Validate reads the rest of the CSV, checking it against the schema. Records
+// that cannot be read and values that break the rules are collected in the
+// report instead of stopping the read. ReadHeading must be called first if
+// there are headings. An error is returned only if reading cannot continue.
+func (r *Reader) Validate(s *Schema) (*Report, error) {
+	rep := &Report{}
+	for _, name := range s.Required {
+		found := false
+		for _, h := range r.headings {
+			if h == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rep.Missing = append(rep.Missing, name)
+		}
+	}
+
+	var rules []rule
+	for {
+		record, err := r.Read()
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			rep.Errors = append(rep.Errors, perr)
+			continue
+		}
+		if err != nil {
+			return rep, err
+		}
+		if record == nil {
+			if rep.Records == 0 {
+				r.schemaRules(s, rep)
+			}
+			return rep, nil
+		}
+		if rep.Records == 0 {
+			rules = r.schemaRules(s, rep)
+		}
+		rep.Records++
+		for _, c := range rules {
+			v := record[c.idx]
+			var err error
+			switch {
+			case math.IsNaN(v):
+				if c.NotNaN {
+					err = ErrMissing
+				}
+			case (c.HasMin && v < c.Min) || (c.HasMax && v > c.Max):
+				err = ErrRange
+			}
+			if err != nil {
+				rep.Violations = append(rep.Violations, Violation{Line: r.line, Column: c.name, Value: v, Err: err})
+			}
+		}
+	}
+}
+
+// schemaRules finds the columns of the rules of s in the record, adding those
+// not found to rep.Missing. If no record has been read, only the columns not
+// in the file are missing, and the rules returned are incomplete.
+func (r *Reader) schemaRules(s *Schema, rep *Report) []rule {
+	rules := make([]rule, 0, len(s.Columns))
+	for _, c := range s.Columns {
+		name := c.Name
+		if name == "" {
+			name = strconv.Itoa(c.Index)
+			if c.Index >= 0 && c.Index < len(r.headings) {
+				name = r.headings[c.Index]
+			}
+		}
+		j, err := r.columnIndex(c.Name, c.Index)
+		if err == nil && r.outIndex == nil {
+			continue
+		}
+		if err != nil || r.outIndex[j] < 0 {
+			rep.Missing = append(rep.Missing, name)
+			continue
+		}
+		rules = append(rules, rule{ColumnRule: c, name: name, idx: r.outIndex[j]})
+	}
+	return rules
+}
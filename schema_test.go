@@ -0,0 +1,53 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateBounds(t *testing.T) {
+	for _, test := range []struct {
+		rule ColumnRule
+		bad  []float64
+	}{
+		{ColumnRule{Name: "a"}, nil},
+		{ColumnRule{Name: "a", Min: 5, HasMin: true}, []float64{4}},
+		{ColumnRule{Name: "a", Max: 5, HasMax: true}, []float64{6, 7}},
+		{ColumnRule{Name: "a", Min: 0, Max: 6, HasMin: true, HasMax: true}, []float64{7}},
+		{ColumnRule{Name: "a", NotNaN: true}, []float64{0}},
+	} {
+		r := NewReader(strings.NewReader("a\n4\n6\n7\nNA\n"))
+		r.NaNTokens = []string{"NA"}
+		if _, err := r.ReadHeading(); err != nil {
+			t.Fatal(err)
+		}
+		rep, err := r.Validate(&Schema{Columns: []ColumnRule{test.rule}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rep.Violations) != len(test.bad) {
+			t.Errorf("%+v: violations %v, want values %v", test.rule, rep.Violations, test.bad)
+			continue
+		}
+		for i, v := range rep.Violations {
+			if v.Err == ErrRange && v.Value != test.bad[i] {
+				t.Errorf("%+v: violation %v, want value %v", test.rule, v, test.bad[i])
+			}
+		}
+	}
+}
+
+func TestValidateNoRecords(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n"))
+	if _, err := r.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := r.Validate(&Schema{Columns: []ColumnRule{{Name: "a"}, {Name: "c"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rep.Missing, []string{"c"}) || rep.OK() {
+		t.Errorf("Missing = %q, OK = %v, want [c], false", rep.Missing, rep.OK())
+	}
+}
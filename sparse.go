@@ -0,0 +1,130 @@
+package numcsv
+
+import "sort"
+
+// Triplets is a sparse matrix stored in coordinate form. The value at row
+// I[k] and column J[k] is V[k], and all other values are zero.
+type Triplets struct {
+	Rows, Cols int
+	I, J       []int
+	V          []float64
+}
+
+// ReadAllSparse is like ReadAllSlice, but stores only the non-zero values,
+// so that large mostly zero files can be read without allocating every
+// record. Records are kept as in ReadAllSlice according to RowFilter,
+// MaxRows, ImputeDrop and Dedupe, but other missing values are not imputed,
+// the columns are not normalized and one-hot columns are not expanded. The
+// columns are selected by ColumnOrder. If Transpose is set the transpose is
+// returned.
+func (r *Reader) ReadAllSparse() (*Triplets, error) {
+	t := &Triplets{Cols: r.FieldsPerRecord}
+	var col []int     // column of each value of the record, or -1 if not selected
+	var span [][2]int // range in V of the values of each row, kept for DedupeLast
+	var dd *deduper
+	replaced := false // a row was replaced by a later duplicate
+	add := func(i int, data []float64) {
+		for j, v := range data {
+			if v != 0 && col[j] >= 0 {
+				t.I = append(t.I, i)
+				t.J = append(t.J, col[j])
+				t.V = append(t.V, v)
+			}
+		}
+	}
+	for r.MaxRows <= 0 || t.Rows < r.MaxRows {
+		data, err := r.Read()
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			break
+		}
+		r.progress(false)
+		if r.RowFilter != nil && !r.RowFilter(data) {
+			continue
+		}
+		if r.Impute == ImputeDrop && hasNaN(data) {
+			continue
+		}
+		if col == nil {
+			col = r.sparseColumns(len(data))
+		}
+		if r.Dedupe != DedupeNone {
+			if dd == nil {
+				if dd, err = r.newDeduper(); err != nil {
+					return nil, err
+				}
+			}
+			if i, dup := dd.find(data, t.Rows); dup {
+				if r.Dedupe == DedupeLast {
+					// Mark the values of the first record as removed with a
+					// zero, which is never stored
+					for k := span[i][0]; k < span[i][1]; k++ {
+						t.V[k] = 0
+					}
+					span[i][0] = len(t.V)
+					add(i, data)
+					span[i][1] = len(t.V)
+					replaced = true
+				}
+				continue
+			}
+		}
+		k := len(t.V)
+		add(t.Rows, data)
+		if r.Dedupe == DedupeLast {
+			span = append(span, [2]int{k, len(t.V)})
+		}
+		t.Rows++
+	}
+	r.progress(true)
+	if replaced {
+		t.compact()
+	}
+	if r.isSetup {
+		t.Cols = len(r.outColumns())
+	}
+	if r.Transpose {
+		t.Rows, t.Cols = t.Cols, t.Rows
+		t.I, t.J = t.J, t.I
+	}
+	return t, nil
+}
+
+// compact removes the zero values, and sorts the values by row
+func (t *Triplets) compact() {
+	idx := make([]int, 0, len(t.V))
+	for k, v := range t.V {
+		if v != 0 {
+			idx = append(idx, k)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return t.I[idx[a]] < t.I[idx[b]] })
+	I := make([]int, len(idx))
+	J := make([]int, len(idx))
+	V := make([]float64, len(idx))
+	for n, k := range idx {
+		I[n], J[n], V[n] = t.I[k], t.J[k], t.V[k]
+	}
+	t.I, t.J, t.V = I, J, V
+}
+
+// sparseColumns returns the column in the output of each of the n values of a
+// record, or -1 for the values not selected by ColumnOrder
+func (r *Reader) sparseColumns(n int) []int {
+	col := make([]int, n)
+	if r.order == nil {
+		for j := range col {
+			col[j] = j
+		}
+		return col
+	}
+	for j := range col {
+		col[j] = -1
+	}
+	for k, j := range r.order {
+		col[j] = k
+	}
+	return col
+}
@@ -0,0 +1,64 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAllSparseColumnOrder(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b,c\n1,0,2\n0,3,0\n"))
+	r.ColumnOrder = []string{"c", "a"}
+	r.ReadHeading()
+	got, err := r.ReadAllSparse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Triplets{Rows: 2, Cols: 2, I: []int{0, 0}, J: []int{1, 0}, V: []float64{1, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if h := r.DataHeadings(); !reflect.DeepEqual(h, []string{"c", "a"}) {
+		t.Errorf("DataHeadings = %q", h)
+	}
+}
+
+func TestReadAllSparseKept(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		setup func(r *Reader)
+		want  *Triplets
+	}{
+		{
+			name:  "RowFilter",
+			setup: func(r *Reader) { r.RowFilter = func(data []float64) bool { return data[0] != 2 } },
+			want:  &Triplets{Rows: 3, Cols: 2, I: []int{0, 1, 2, 2}, J: []int{0, 0, 0, 1}, V: []float64{1, 3, 1, 5}},
+		},
+		{
+			name:  "MaxRows",
+			setup: func(r *Reader) { r.MaxRows = 2 },
+			want:  &Triplets{Rows: 2, Cols: 2, I: []int{0, 1}, J: []int{0, 0}, V: []float64{1, 2}},
+		},
+		{
+			name:  "DedupeFirst",
+			setup: func(r *Reader) { r.Dedupe, r.DedupeKey = DedupeFirst, "k" },
+			want:  &Triplets{Rows: 3, Cols: 2, I: []int{0, 1, 2}, J: []int{0, 0, 0}, V: []float64{1, 2, 3}},
+		},
+		{
+			name:  "DedupeLast",
+			setup: func(r *Reader) { r.Dedupe, r.DedupeKey = DedupeLast, "k" },
+			want:  &Triplets{Rows: 3, Cols: 2, I: []int{0, 0, 1, 2}, J: []int{0, 1, 0, 0}, V: []float64{1, 5, 2, 3}},
+		},
+	} {
+		r := NewReader(strings.NewReader("k,v\n1,0\n2,0\n3,0\n1,5\n"))
+		r.ReadHeading()
+		test.setup(r)
+		got, err := r.ReadAllSparse()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package numcsv
+
+import "strings"
+
+// Splitter splits a line of the file into its fields. The Reader trims the
+// whitespace around the fields and ignores empty fields, so a Splitter does
+// not need to.
+type Splitter interface {
+	Split(line string) []string
+}
+
+// CommaSplitter splits lines at every occurrence of the delimiter, as the
+// Reader does when it has no Splitter
+type CommaSplitter string
+
+func (c CommaSplitter) Split(line string) []string {
+	return strings.Split(line, string(c))
+}
+
+// WhitespaceSplitter splits lines at runs of whitespace
+type WhitespaceSplitter struct{}
+
+func (WhitespaceSplitter) Split(line string) []string {
+	return strings.Fields(line)
+}
+
+// QuoteSplitter splits lines at the delimiter, except where it is inside
+// double quotes. The quotes are removed, and a doubled quote inside quotes is
+// read as a single quote.
+type QuoteSplitter string
+
+func (q QuoteSplitter) Split(line string) []string {
+	comma := string(q)
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == '"':
+			if quoted && i+1 < len(line) && line[i+1] == '"' {
+				field.WriteByte('"')
+				i += 2
+				continue
+			}
+			quoted = !quoted
+			i++
+		case !quoted && comma != "" && strings.HasPrefix(line[i:], comma):
+			fields = append(fields, field.String())
+			field.Reset()
+			i += len(comma)
+		default:
+			field.WriteByte(line[i])
+			i++
+		}
+	}
+	return append(fields, field.String())
+}
+
+// FixedWidthSplitter splits lines into columns at fixed byte offsets. Starts
+// holds the offset at which each column starts, and each column ends where
+// the next starts. If Starts is nil, it is inferred from the first line split,
+// which should be the heading: each column ends at the end of its heading, as
+// for right-aligned numbers. It must be used as a pointer so the inferred
+// offsets are kept, and Starts must be reset to nil to infer them again for
+// another file.
+type FixedWidthSplitter struct {
+	Starts []int
+}
+
+func (f *FixedWidthSplitter) Split(line string) []string {
+	if f.Starts == nil {
+		f.Starts = InferFixedWidth(line)
+	}
+	fields := make([]string, 0, len(f.Starts))
+	for j, start := range f.Starts {
+		if start >= len(line) {
+			break
+		}
+		end := len(line)
+		if j+1 < len(f.Starts) && f.Starts[j+1] < end {
+			end = f.Starts[j+1]
+		}
+		fields = append(fields, line[start:end])
+	}
+	return fields
+}
+
+// InferFixedWidth returns the offsets of the columns of a fixed-width heading.
+// The first column starts at 0, and each other column starts at the end of
+// the heading before it.
+func InferFixedWidth(heading string) []int {
+	var starts []int
+	inWord := false
+	for i := 0; i < len(heading); i++ {
+		space := heading[i] == ' ' || heading[i] == '\t'
+		switch {
+		case !space && !inWord:
+			if starts == nil {
+				starts = append(starts, 0)
+			}
+			inWord = true
+		case space && inWord:
+			starts = append(starts, i)
+			inWord = false
+		}
+	}
+	if !inWord && len(starts) > 0 {
+		// Remove the start after the last heading
+		starts = starts[:len(starts)-1]
+	}
+	return starts
+}
@@ -0,0 +1,91 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuoteSplitter(t *testing.T) {
+	for _, test := range []struct {
+		comma string
+		line  string
+		want  []string
+	}{
+		{",", "a,b,c", []string{"a", "b", "c"}},
+		{",", "", []string{""}},
+		{",", "a,,b,", []string{"a", "", "b", ""}},
+		{",", `"a,b",c`, []string{"a,b", "c"}},
+		{",", `"a ""q"" b",c`, []string{`a "q" b`, "c"}},
+		{",", `""`, []string{""}},
+		{",", `""""`, []string{`"`}},
+		{",", `x"a,b"y,c`, []string{"xa,by", "c"}},
+		{",", `"a,b`, []string{"a,b"}},
+		{";", `"1,5";2`, []string{"1,5", "2"}},
+		{"::", `a::"b::c"::d`, []string{"a", "b::c", "d"}},
+		{"", `a,"b"`, []string{"a,b"}},
+		{",", "é,\"ü,ß\"", []string{"é", "ü,ß"}},
+	} {
+		got := QuoteSplitter(test.comma).Split(test.line)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("QuoteSplitter(%q).Split(%q) = %q, want %q", test.comma, test.line, got, test.want)
+		}
+	}
+}
+
+// QuoteSplitter must agree with CommaSplitter on lines without quotes
+func TestQuoteSplitterUnquoted(t *testing.T) {
+	for _, line := range []string{"", ",", "1,2,3", " 1 , 2 ,", ",,a,,", "1;2"} {
+		got := QuoteSplitter(",").Split(line)
+		want := CommaSplitter(",").Split(line)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("QuoteSplitter.Split(%q) = %q, CommaSplitter.Split = %q", line, got, want)
+		}
+	}
+}
+
+func TestReaderQuoteSplitter(t *testing.T) {
+	r := NewReader(strings.NewReader("\"x, m\",\"y\"\n\"1\",2\n3,\"4\"\n"))
+	r.Splitter = QuoteSplitter(",")
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x, m", "y"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestWhitespaceSplitter(t *testing.T) {
+	got := WhitespaceSplitter{}.Split(" 1\t 2  3 ")
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Split = %q, want %q", got, want)
+	}
+}
+
+func TestFixedWidthSplitter(t *testing.T) {
+	f := &FixedWidthSplitter{}
+	if got, want := f.Split("   time  value"), []string{"   time", "  value"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("heading Split = %q, want %q", got, want)
+	}
+	if want := []int{0, 7}; !reflect.DeepEqual(f.Starts, want) {
+		t.Errorf("Starts = %v, want %v", f.Starts, want)
+	}
+	for _, test := range []struct {
+		line string
+		want []string
+	}{
+		{"    1.5   -2.0", []string{"    1.5", "   -2.0"}},
+		{"   10.0", []string{"   10.0"}},
+		{"  100.5", []string{"  100.5"}},
+		{"123456789", []string{"1234567", "89"}},
+		{"", []string{}},
+	} {
+		if got := f.Split(test.line); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Split(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package numcsv
+
+import "math"
+
+// Stats holds statistics of each column of the records, computed while they
+// are read, or after the read if a record is replaced by a later duplicate
+// with DedupeLast. Missing (NaN) values are counted in Missing and are
+// otherwise ignored.
+type Stats struct {
+	Count   []int // number of values which are not missing
+	Missing []int
+	Min     []float64
+	Max     []float64
+	Mean    []float64
+	Std     []float64 // sample standard deviation
+	m2      []float64 // sum of squared differences from the mean
+}
+
+func newStats(n int) *Stats {
+	s := &Stats{
+		Count:   make([]int, n),
+		Missing: make([]int, n),
+		Min:     make([]float64, n),
+		Max:     make([]float64, n),
+		Mean:    make([]float64, n),
+		Std:     make([]float64, n),
+		m2:      make([]float64, n),
+	}
+	for j := range s.Min {
+		s.Min[j] = math.Inf(1)
+		s.Max[j] = math.Inf(-1)
+	}
+	return s
+}
+
+// add updates the statistics with a record using Welford's algorithm
+func (s *Stats) add(data []float64) {
+	for j, v := range data {
+		if math.IsNaN(v) {
+			s.Missing[j]++
+			continue
+		}
+		s.Count[j]++
+		s.Min[j] = math.Min(s.Min[j], v)
+		s.Max[j] = math.Max(s.Max[j], v)
+		d := v - s.Mean[j]
+		s.Mean[j] += d / float64(s.Count[j])
+		s.m2[j] += d * (v - s.Mean[j])
+	}
+}
+
+func (s *Stats) finish() {
+	for j, n := range s.Count {
+		switch n {
+		case 0:
+			s.Mean[j] = math.NaN()
+			s.Std[j] = math.NaN()
+		case 1:
+			s.Std[j] = 0
+		default:
+			s.Std[j] = math.Sqrt(s.m2[j] / float64(n-1))
+		}
+	}
+}
+
+// ReadAllStats is like ReadAllSlice, but also returns statistics of each column
+// of the records. The statistics are in the order of the columns of the
+// returned records, or of their rows if Transpose is set. Records dropped by
+// ImputeDrop are not included, and missing values are counted before they are
+// filled.
+func (r *Reader) ReadAllStats() ([][]float64, *Stats, error) {
+	alldata, stats, err := r.readRecords(nil, true)
+	if err != nil {
+		return nil, nil, r.partialError(alldata, err)
+	}
+	if !r.isSetup {
+		// No record was read, so there is a column for each heading
+		stats = newStats(len(r.DataHeadings()))
+		stats.finish()
+		return r.arrange(alldata), stats, nil
+	}
+	if stats == nil {
+		stats = newStats(r.width)
+		stats.finish()
+	}
+	return r.arrange(alldata), r.arrangeStats(stats, alldata), nil
+}
+
+// arrangeStats returns the statistics of the columns of the records returned
+// by arrange, given those of the records in alldata. The statistics of the
+// one-hot columns are computed from the labels in alldata.
+func (r *Reader) arrangeStats(s *Stats, alldata [][]float64) *Stats {
+	span := r.oneHotSpans()
+	if span == nil && r.order == nil {
+		return s
+	}
+	out := &Stats{}
+	for _, j := range r.outColumns() {
+		if span == nil || span[j] == 0 {
+			out.appendColumn(s, j)
+			continue
+		}
+		oh := newStats(span[j])
+		rec := make([]float64, span[j])
+		for _, record := range alldata {
+			for m := range rec {
+				rec[m] = 0
+			}
+			if v := record[j]; !math.IsNaN(v) {
+				rec[int(v)] = 1
+			}
+			oh.add(rec)
+		}
+		oh.finish()
+		for m := range rec {
+			out.appendColumn(oh, m)
+		}
+	}
+	return out
+}
+
+// appendColumn appends the statistics of column j of t
+func (s *Stats) appendColumn(t *Stats, j int) {
+	s.Count = append(s.Count, t.Count[j])
+	s.Missing = append(s.Missing, t.Missing[j])
+	s.Min = append(s.Min, t.Min[j])
+	s.Max = append(s.Max, t.Max[j])
+	s.Mean = append(s.Mean, t.Mean[j])
+	s.Std = append(s.Std, t.Std[j])
+	s.m2 = append(s.m2, t.m2[j])
+}
@@ -0,0 +1,72 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAllStats(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n1,20\nNA,30\n3,NA\n"))
+	r.NaNTokens = []string{"NA"}
+	r.ReadHeading()
+	_, s, err := r.ReadAllStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Count, []int{2, 2}) || !reflect.DeepEqual(s.Missing, []int{1, 1}) {
+		t.Errorf("count = %v, missing = %v", s.Count, s.Missing)
+	}
+	if !reflect.DeepEqual(s.Mean, []float64{2, 25}) || !reflect.DeepEqual(s.Min, []float64{1, 20}) || !reflect.DeepEqual(s.Max, []float64{3, 30}) {
+		t.Errorf("mean = %v, min = %v, max = %v", s.Mean, s.Min, s.Max)
+	}
+	if math.Abs(s.Std[0]-math.Sqrt2) > 1e-12 {
+		t.Errorf("std = %v, want %v", s.Std[0], math.Sqrt2)
+	}
+}
+
+func TestReadAllStatsNoRecords(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n"))
+	r.ReadHeading()
+	_, s, err := r.ReadAllStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Count) != 2 || s.Count[1] != 0 || !math.IsNaN(s.Mean[1]) {
+		t.Errorf("count = %v, mean = %v, want 2 columns with no values", s.Count, s.Mean)
+	}
+}
+
+func TestReadAllStatsArranged(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n1,20\n2,30\n"))
+	r.ColumnOrder = []string{"b"}
+	r.ReadHeading()
+	data, s, err := r.ReadAllStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{20}, {30}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	if !reflect.DeepEqual(s.Mean, []float64{25}) {
+		t.Errorf("mean = %v, want [25]", s.Mean)
+	}
+
+	r = NewReader(strings.NewReader("c,v\nx,1\ny,2\nx,3\n"))
+	r.CategoricalColumns = []CategoricalColumn{{Name: "c", OneHot: true}}
+	r.ReadHeading()
+	_, s, err = r.ReadAllStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{2.0 / 3, 1.0 / 3, 2}
+	if len(s.Mean) != len(want) {
+		t.Fatalf("one-hot mean = %v, want %v", s.Mean, want)
+	}
+	for j := range want {
+		if math.Abs(s.Mean[j]-want[j]) > 1e-12 {
+			t.Errorf("one-hot mean = %v, want %v", s.Mean, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package numcsv
+
+// Table holds all of the records of a CSV. The numeric columns are stored in
+// Data, and the string columns are accessed by heading with Strings, or by
+// index with StringsAt. The integer columns are in Data, and are additionally
+// stored exactly.
+type Table struct {
+	Headings []string // headings of the columns of Data. nil if ReadHeading was not called
+	Data     [][]float64
+	columns  map[string]int // index in the file of the first column with each heading
+	ints     map[int][]int64
+	strs     map[int][]string
+}
+
+// Ints returns the exact values of the integer column with the given heading.
+// Missing values are 0, and are NaN in Data before imputation. Returns nil if there is no such integer column.
+func (t *Table) Ints(name string) []int64 {
+	j, ok := t.columns[name]
+	if !ok {
+		return nil
+	}
+	return t.ints[j]
+}
+
+// IntsAt returns the exact values of the integer column with index j in the
+// file. Missing values are 0, as in Ints. Returns nil if there is no such integer column.
+func (t *Table) IntsAt(j int) []int64 {
+	return t.ints[j]
+}
+
+// Strings returns the values of the string column with the given heading.
+// Returns nil if there is no such string column.
+func (t *Table) Strings(name string) []string {
+	j, ok := t.columns[name]
+	if !ok {
+		return nil
+	}
+	return t.strs[j]
+}
+
+// StringsAt returns the values of the string column with index j in the
+// file. Returns nil if there is no such string column.
+func (t *Table) StringsAt(j int) []string {
+	return t.strs[j]
+}
+
+// ReadTable reads all of the records from the CSV. It is like ReadAllSlice, but
+// also keeps the exact values of the integer columns and the values of the
+// string columns. ReadHeading must be called first if there are headings.
+// Errors are returned as in ReadAllSlice.
+func (r *Reader) ReadTable() (*Table, error) {
+	ints := make(map[int][]int64)
+	strs := make(map[int][]string)
+	alldata, _, err := r.readRecords(func(i int, _ []float64) {
+		for j, c := range r.cols {
+			switch {
+			case c.kind == intKind && i < len(ints[j]):
+				ints[j][i] = r.ints[j]
+			case c.kind == intKind:
+				ints[j] = append(ints[j], r.ints[j])
+			case c.kind == stringKind && i < len(strs[j]):
+				strs[j][i] = r.strs[j]
+			case c.kind == stringKind:
+				strs[j] = append(strs[j], r.strs[j])
+			}
+		}
+	}, false)
+	if err != nil {
+		return nil, r.partialError(alldata, err)
+	}
+	columns := make(map[string]int, len(r.headings))
+	for j := len(r.headings) - 1; j >= 0; j-- {
+		columns[r.headings[j]] = j
+	}
+	return &Table{
+		Headings: r.matHeadings(),
+		Data:     r.arrange(alldata),
+		columns:  columns,
+		ints:     ints,
+		strs:     strs,
+	}, nil
+}
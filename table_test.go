@@ -0,0 +1,57 @@
+package numcsv
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableColumns(t *testing.T) {
+	r := NewReader(strings.NewReader("7,x,1.5\n9,y,2\n"))
+	r.NoHeading = true
+	r.IntColumns = []Column{{Index: 0}}
+	r.StringColumns = []Column{{Index: 1}}
+	tab, err := r.ReadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tab.IntsAt(0), []int64{7, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IntsAt(0) = %v, want %v", got, want)
+	}
+	if got, want := tab.StringsAt(1), []string{"x", "y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StringsAt(1) = %q, want %q", got, want)
+	}
+	if want := [][]float64{{7, 1.5}, {9, 2}}; !reflect.DeepEqual(tab.Data, want) {
+		t.Errorf("Data = %v, want %v", tab.Data, want)
+	}
+
+	r = NewReader(strings.NewReader("i,s\n7,x\n"))
+	r.IntColumns = []Column{{Name: "i"}}
+	r.StringColumns = []Column{{Name: "s"}}
+	r.ReadHeading()
+	tab, err = r.ReadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tab.Ints("i"), []int64{7}) || !reflect.DeepEqual(tab.Strings("s"), []string{"x"}) || tab.Strings("i") != nil {
+		t.Errorf("Ints = %v, Strings = %q", tab.Ints("i"), tab.Strings("s"))
+	}
+}
+
+func TestTableMissingInt(t *testing.T) {
+	r := NewReader(strings.NewReader("id,v\n7,1\nNA,2\n9,3\n"))
+	r.IntColumns = []Column{{Name: "id"}}
+	r.NaNTokens = []string{"NA"}
+	r.ReadHeading()
+	tab, err := r.ReadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tab.Ints("id"), []int64{7, 0, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ints(id) = %v, want %v", got, want)
+	}
+	if !math.IsNaN(tab.Data[1][0]) {
+		t.Errorf("Data[1][0] = %v, want NaN", tab.Data[1][0])
+	}
+}
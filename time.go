@@ -0,0 +1,118 @@
+package numcsv
+
+import "time"
+
+// TimeColumn declares a column of timestamps. Each timestamp is converted to
+// the number of seconds since a reference time.
+type TimeColumn struct {
+	Name     string         // heading of the column. If "", Index is used instead
+	Index    int            // index of the column in the record
+	Layout   string         // layout of the timestamps as in time.Parse. If "", the layout is detected
+	Location *time.Location // time zone of timestamps without an explicit offset. If nil, UTC is used
+
+	// Epoch is the reference time for the output values. If zero, the Unix
+	// epoch is used. Ignored if SinceFirst is true.
+	Epoch      time.Time
+	SinceFirst bool // use the first timestamp in the column as the reference time
+
+	// Offset is added to every value, for example to place a file on the time
+	// axis of the files before it. See also Reader.ContinueTime.
+	Offset float64
+}
+
+// timeLayouts are the layouts tried in order when TimeColumn.Layout is not set
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+type timeColumn struct {
+	TimeColumn
+	first   time.Time
+	started bool
+	n       int     // number of values parsed
+	last    float64 // last value parsed
+	step    float64 // difference between the last two values
+	chained bool    // set Offset so the first value is next
+	next    float64
+	restart bool // only chain if the first value is not after the previous value
+}
+
+func (c *timeColumn) parse(str string) (float64, error) {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := c.Layout
+	if layout == "" {
+		// Use the first layout which matches for the rest of the column. If
+		// none match, the next value is tried again.
+		layout = time.RFC3339
+		for _, l := range timeLayouts {
+			if _, err := time.ParseInLocation(l, str, loc); err == nil {
+				c.Layout, layout = l, l
+				break
+			}
+		}
+	}
+	t, err := time.ParseInLocation(layout, str, loc)
+	if err != nil {
+		return 0, err
+	}
+	epoch := c.Epoch
+	switch {
+	case c.SinceFirst:
+		if !c.started {
+			c.first = t
+			c.started = true
+		}
+		epoch = c.first
+	case epoch.IsZero():
+		epoch = time.Unix(0, 0)
+	}
+	v := seconds(t, epoch)
+	if c.n == 0 && c.chained && (!c.restart || v+c.Offset <= c.last) {
+		c.Offset = c.next - v
+	}
+	v += c.Offset
+	if c.n > 0 {
+		c.step = v - c.last
+	}
+	c.last = v
+	c.n++
+	return v, nil
+}
+
+// ContinueTime sets the time columns to continue from where they ended in
+// prev, which has been used to read the file before this one. The Offset of
+// each of the TimeColumns is set so that its first value is one time step
+// (between the last two values of prev) after the last value of the same
+// column in prev. Columns are matched by their position in TimeColumns. It
+// must be called before the first record is read.
+func (r *Reader) ContinueTime(prev *Reader) {
+	r.prevTimes = prev.times
+}
+
+// continueFiles chains the time columns to the first record of a later file
+// of a Reader from NewMultiReader, where their times may restart
+func (r *Reader) continueFiles() {
+	for _, c := range r.times {
+		if c.n == 0 {
+			continue
+		}
+		c.chained = true
+		c.restart = true
+		c.next = c.last + c.step
+		c.n = 0
+	}
+}
+
+// seconds returns the number of seconds from epoch to t. Unlike t.Sub it does
+// not saturate for times centuries apart.
+func seconds(t, epoch time.Time) float64 {
+	s := float64(t.Unix() - epoch.Unix())
+	return s + float64(t.Nanosecond()-epoch.Nanosecond())/1e9
+}
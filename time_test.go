@@ -0,0 +1,159 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestTimeLayoutDetection(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  []float64
+	}{
+		{"t\n1970-01-02T00:00:00Z\n1970-01-02T00:00:01+01:00\n", []float64{86400, 86400 - 3599}},
+		{"t\n1970-01-01T00:01:00\n1970-01-01T00:02:00\n", []float64{60, 120}},
+		{"t\n1970-01-01 00:00:10\n1970-01-01 00:00:20\n", []float64{10, 20}},
+		{"t\n1970-01-01 00:00:10+00:00\n", []float64{10}},
+		{"t\n1970-01-03\n1970-01-04\n", []float64{2 * 86400, 3 * 86400}},
+	} {
+		r := NewReader(strings.NewReader(test.input))
+		r.TimeColumns = []TimeColumn{{Name: "t"}}
+		r.ReadHeading()
+		data, err := r.ReadAllSlice()
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+		var got []float64
+		for _, record := range data {
+			got = append(got, record[0])
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestTimeLayoutRetry(t *testing.T) {
+	r := NewReader(strings.NewReader("t\nbad\n1970-01-01 00:00:05\n1970-01-01 00:00:06\n"))
+	r.TimeColumns = []TimeColumn{{Name: "t"}}
+	r.ReadHeading()
+	rep, err := r.Validate(&Schema{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Errors) != 1 || rep.Records != 2 {
+		t.Errorf("errors = %v, records = %d, want 1 error and 2 records", rep.Errors, rep.Records)
+	}
+}
+
+// readTimes reads the time column t of input
+func readTimes(t *testing.T, input string, c TimeColumn) []float64 {
+	t.Helper()
+	r := NewReader(strings.NewReader(input))
+	c.Name = "t"
+	r.TimeColumns = []TimeColumn{c}
+	r.ReadHeading()
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []float64
+	for _, record := range data {
+		got = append(got, record[0])
+	}
+	return got
+}
+
+func TestTimeLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		input string
+		want  []float64
+	}{
+		// Clocks go forward at 2:00 EST, so an hour passes between 1:30 and 3:30
+		{"t\n2024-03-10 01:30:00\n2024-03-10 03:30:00\n", []float64{0, 3600}},
+		// Clocks go back at 2:00 EDT, so three hours pass between 0:30 and 2:30
+		{"t\n2024-11-03 00:30:00\n2024-11-03 02:30:00\n", []float64{0, 3 * 3600}},
+	} {
+		got := readTimes(t, test.input, TimeColumn{Location: ny, SinceFirst: true})
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	// Midnight in New York is 5 hours after midnight UTC in winter, and 4 in summer
+	got := readTimes(t, "t\n2024-01-01\n2024-07-01\n", TimeColumn{Location: ny, Epoch: epoch})
+	summer := float64(182*86400 + 4*3600)
+	if want := []float64{5 * 3600, summer}; !reflect.DeepEqual(got, want) {
+		t.Errorf("epoch: got %v, want %v", got, want)
+	}
+	// An explicit offset overrides the location
+	got = readTimes(t, "t\n2024-01-01T00:00:00Z\n2024-01-01T00:00:00-05:00\n", TimeColumn{Location: ny, Epoch: epoch})
+	if want := []float64{0, 5 * 3600}; !reflect.DeepEqual(got, want) {
+		t.Errorf("offset: got %v, want %v", got, want)
+	}
+}
+
+func TestTimeEpoch(t *testing.T) {
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := readTimes(t, "t\n2000-01-01T00:01:00Z\n1999-12-31T23:59:59.5Z\n", TimeColumn{Epoch: epoch})
+	if want := []float64{60, -0.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// SinceFirst overrides Epoch, and Offset is added to every value
+	got = readTimes(t, "t\n2000-01-01T00:01:00Z\n2000-01-01T00:02:00Z\n", TimeColumn{Epoch: epoch, SinceFirst: true, Offset: 10})
+	if want := []float64{10, 70}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SinceFirst: got %v, want %v", got, want)
+	}
+}
+
+func TestContinueTime(t *testing.T) {
+	const (
+		first  = "t,v\n2020-01-01T00:00:00Z,1\n2020-01-01T00:00:10Z,2\n2020-01-01T00:00:20Z,3\n"
+		second = "t,v\n2020-06-01T00:00:00Z,4\n2020-06-01T00:00:10Z,5\n"
+	)
+	read := func(r *Reader) [][]float64 {
+		t.Helper()
+		r.TimeColumns = []TimeColumn{{Name: "t", SinceFirst: true}}
+		r.ReadHeading()
+		data, err := r.ReadAllSlice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	prev := NewReader(strings.NewReader(first))
+	if got, want := read(prev), [][]float64{{0, 1}, {10, 2}, {20, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first file: %v, want %v", got, want)
+	}
+	// The second file starts one step of 10s after the first ends, although
+	// its times are months later
+	r := NewReader(strings.NewReader(second))
+	r.ContinueTime(prev)
+	if got, want := read(r), [][]float64{{30, 4}, {40, 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second file: %v, want %v", got, want)
+	}
+	// Files can be chained
+	next := NewReader(strings.NewReader(second))
+	next.ContinueTime(r)
+	if got, want := read(next), [][]float64{{50, 4}, {60, 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("third file: %v, want %v", got, want)
+	}
+
+	// A previous file with no records leaves the times unchanged
+	empty := NewReader(strings.NewReader("t,v\n"))
+	read(empty)
+	r = NewReader(strings.NewReader(second))
+	r.ContinueTime(empty)
+	if got, want := read(r), [][]float64{{0, 4}, {10, 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after an empty file: %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package numcsv
+
+import (
+	"math"
+	"strings"
+)
+
+// SplitUnit splits a heading of the form "name (unit)" or "name [unit]" into
+// the name and the unit. If the heading has no unit, unit is "".
+func SplitUnit(heading string) (name, unit string) {
+	heading = strings.TrimSpace(heading)
+	for _, p := range []string{"()", "[]"} {
+		if !strings.HasSuffix(heading, p[1:]) {
+			continue
+		}
+		i := strings.LastIndex(heading, p[:1])
+		if i < 0 {
+			continue
+		}
+		return strings.TrimSpace(heading[:i]), strings.TrimSpace(heading[i+1 : len(heading)-1])
+	}
+	return heading, ""
+}
+
+// UnitConversion converts values in one unit to the unit To, as
+// v*Scale + Offset
+type UnitConversion struct {
+	To     string
+	Scale  float64
+	Offset float64
+}
+
+// Units returns the units of the columns found by ReadHeading, or nil if
+// ParseUnits is not set. Columns without a unit have a unit of "". The units
+// of converted columns are the units they are converted to.
+func (r *Reader) Units() []string {
+	return r.units
+}
+
+// findConversions finds the UnitConversions of the units of the columns, and
+// replaces the units with the units converted to
+func (r *Reader) findConversions() {
+	r.unitConv = nil
+	if r.UnitConversions == nil {
+		return
+	}
+	for j, unit := range r.units {
+		c, ok := r.UnitConversions[unit]
+		if !ok {
+			continue
+		}
+		if r.unitConv == nil {
+			r.unitConv = make([]*UnitConversion, len(r.units))
+		}
+		r.unitConv[j] = &c
+		r.units[j] = c.To
+	}
+}
+
+// convertUnit converts a value of column j to its new unit. If sigma is set, v
+// is an uncertainty and is only scaled.
+func (r *Reader) convertUnit(j int, v float64, sigma bool) float64 {
+	if r.unitConv == nil || r.unitConv[j] == nil {
+		return v
+	}
+	c := r.unitConv[j]
+	if sigma {
+		return v * math.Abs(c.Scale)
+	}
+	return v*c.Scale + c.Offset
+}
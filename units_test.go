@@ -0,0 +1,101 @@
+package numcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitUnit(t *testing.T) {
+	for _, test := range []struct {
+		heading, name, unit string
+	}{
+		{"speed (m/s)", "speed", "m/s"},
+		{"speed [m/s]", "speed", "m/s"},
+		{"  speed ( m/s )  ", "speed", "m/s"},
+		{"speed(m/s)", "speed", "m/s"},
+		{"speed", "speed", ""},
+		{"speed ()", "speed", ""},
+		{"f (x) (Hz)", "f (x)", "Hz"},
+		{"speed (m/s", "speed (m/s", ""},
+		{"speed m/s)", "speed m/s)", ""},
+		{"(m/s)", "", "m/s"},
+		{"", "", ""},
+	} {
+		name, unit := SplitUnit(test.heading)
+		if name != test.name || unit != test.unit {
+			t.Errorf("SplitUnit(%q) = %q, %q, want %q, %q", test.heading, name, unit, test.name, test.unit)
+		}
+	}
+}
+
+func TestUnitConversions(t *testing.T) {
+	const input = "t (min),T (degC),p [kPa],n\n1,0,100,1\n2,100,101.5,2\n"
+	conversions := map[string]UnitConversion{
+		"min":  {To: "s", Scale: 60},
+		"degC": {To: "K", Scale: 1, Offset: 273.15},
+	}
+	for _, test := range []struct {
+		name        string
+		strip       bool
+		conversions map[string]UnitConversion
+		headings    []string
+		units       []string
+		data        [][]float64
+	}{
+		{
+			name:     "units only",
+			headings: []string{"t (min)", "T (degC)", "p [kPa]", "n"},
+			units:    []string{"min", "degC", "kPa", ""},
+			data:     [][]float64{{1, 0, 100, 1}, {2, 100, 101.5, 2}},
+		},
+		{
+			name:     "strip",
+			strip:    true,
+			headings: []string{"t", "T", "p", "n"},
+			units:    []string{"min", "degC", "kPa", ""},
+			data:     [][]float64{{1, 0, 100, 1}, {2, 100, 101.5, 2}},
+		},
+		{
+			name:        "converted",
+			strip:       true,
+			conversions: conversions,
+			headings:    []string{"t", "T", "p", "n"},
+			units:       []string{"s", "K", "kPa", ""},
+			data:        [][]float64{{60, 273.15, 100, 1}, {120, 373.15, 101.5, 2}},
+		},
+	} {
+		r := NewReader(strings.NewReader(input))
+		r.ParseUnits = true
+		r.StripUnits = test.strip
+		r.UnitConversions = test.conversions
+		headings, data, err := r.ReadAllWithHeading()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(headings, test.headings) {
+			t.Errorf("%s: headings = %q, want %q", test.name, headings, test.headings)
+		}
+		if got := r.Units(); !reflect.DeepEqual(got, test.units) {
+			t.Errorf("%s: units = %q, want %q", test.name, got, test.units)
+		}
+		if !reflect.DeepEqual(data, test.data) {
+			t.Errorf("%s: data = %v, want %v", test.name, data, test.data)
+		}
+	}
+
+	// Without ParseUnits the conversions are not applied
+	r := NewReader(strings.NewReader(input))
+	r.UnitConversions = conversions
+	_, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Units() != nil {
+		t.Errorf("units = %q, want nil", r.Units())
+	}
+	if want := [][]float64{{1, 0, 100, 1}, {2, 100, 101.5, 2}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data without ParseUnits = %v, want %v", data, want)
+	}
+}
@@ -0,0 +1,206 @@
+// package numcsv is version 2 of the numcsv API. It is a thin layer over
+// github.com/btracey/numcsv with options-based constructors, io.EOF at the
+// end of the records as in encoding/csv, and a Dataset type read and written
+// whole. Code using the original Reader can migrate incrementally with
+// FromV1 and V1, which share the underlying reader.
+
+package numcsv
+
+import (
+	"io"
+
+	v1 "github.com/btracey/numcsv"
+)
+
+// Option configures a Reader. The options of the original package may also
+// be used, including its dialects such as v1.DialectExcel.
+type Option = v1.Option
+
+// WithComma sets the field delimiter
+func WithComma(comma string) Option {
+	return v1.WithComma(comma)
+}
+
+// WithComment sets the prefix of comment lines
+func WithComment(comment string) Option {
+	return v1.WithComment(comment)
+}
+
+// WithNaNTokens sets the tokens read as missing values
+func WithNaNTokens(tokens ...string) Option {
+	return v1.WithNaNTokens(tokens...)
+}
+
+// WithNoHeading sets that the file has no heading line
+func WithNoHeading() Option {
+	return v1.WithNoHeading()
+}
+
+// WithUnits parses the units from the headings, removing them from the names
+func WithUnits() Option {
+	return v1.WithUnits()
+}
+
+// Dataset is the headings and records of a file
+type Dataset struct {
+	Headings []string // headings of the columns of Data. nil if the file has no heading
+	Units    []string // units of the columns of the file. nil unless the units are parsed
+	Data     [][]float64
+}
+
+// Dims returns the number of records and the number of values in each
+func (d *Dataset) Dims() (r, c int) {
+	if len(d.Data) == 0 {
+		return 0, len(d.Headings)
+	}
+	return len(d.Data), len(d.Data[0])
+}
+
+// Column returns a copy of the values of the column with the given heading,
+// or nil if there is no such column
+func (d *Dataset) Column(name string) []float64 {
+	for j, h := range d.Headings {
+		if h != name {
+			continue
+		}
+		col := make([]float64, len(d.Data))
+		for i, record := range d.Data {
+			col[i] = record[j]
+		}
+		return col
+	}
+	return nil
+}
+
+// Reader reads records from a CSV. The heading, unless WithNoHeading is
+// given, is read before the first record.
+type Reader struct {
+	r        *v1.Reader
+	headings []string
+	started  bool
+}
+
+// NewReader returns a Reader reading from r
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	return FromV1(v1.NewReader(r, opts...))
+}
+
+// FromV1 returns a Reader which reads using r. If the heading of r has
+// already been read, it is not read again.
+func FromV1(r *v1.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// V1 returns the underlying Reader, for the settings without an Option
+func (r *Reader) V1() *v1.Reader {
+	return r.r
+}
+
+func (r *Reader) start() error {
+	if r.started {
+		return nil
+	}
+	r.started = true
+	if r.r.NoHeading || r.r.Headings() != nil {
+		r.headings = r.r.Headings()
+		return nil
+	}
+	var err error
+	r.headings, err = r.r.ReadHeading()
+	return err
+}
+
+// Headings returns the headings of the file, reading them if needed
+func (r *Reader) Headings() ([]string, error) {
+	if err := r.start(); err != nil {
+		return nil, err
+	}
+	return r.headings, nil
+}
+
+// Read reads a single record. It returns io.EOF after the last record.
+func (r *Reader) Read() ([]float64, error) {
+	if err := r.start(); err != nil {
+		return nil, err
+	}
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, io.EOF
+	}
+	return record, nil
+}
+
+// ReadAll reads the headings and all of the remaining records. Unlike Read,
+// it does not return io.EOF.
+func (r *Reader) ReadAll() (*Dataset, error) {
+	if err := r.start(); err != nil {
+		return nil, err
+	}
+	data, err := r.r.ReadAllSlice()
+	if err != nil {
+		return nil, err
+	}
+	return &Dataset{
+		Headings: r.r.DataHeadings(),
+		Units:    r.r.Units(),
+		Data:     data,
+	}, nil
+}
+
+// Writer writes records to a CSV
+type Writer struct {
+	w *v1.Writer
+}
+
+// WriterOption configures a Writer
+type WriterOption func(*v1.Writer)
+
+// WithDelimiter sets the field delimiter of a Writer
+func WithDelimiter(comma string) WriterOption {
+	return func(w *v1.Writer) { w.Comma = comma }
+}
+
+// WithFormat sets the format and precision of the values as in strconv.FormatFloat
+func WithFormat(fmt byte, prec int) WriterOption {
+	return func(w *v1.Writer) { w.FloatFmt, w.Precision = fmt, prec }
+}
+
+// NewWriter returns a Writer writing to w. Values are written with the
+// shortest representation that reads back exactly, unless WithFormat is given.
+func NewWriter(w io.Writer, opts ...WriterOption) *Writer {
+	v := v1.NewWriter(w)
+	v.FloatFmt, v.Precision = 'g', -1
+	for _, opt := range opts {
+		opt(v)
+	}
+	return &Writer{w: v}
+}
+
+// V1 returns the underlying Writer
+func (w *Writer) V1() *v1.Writer {
+	return w.w
+}
+
+// WriteHeading writes the headings
+func (w *Writer) WriteHeading(headings []string) error {
+	return w.w.WriteHeading(headings)
+}
+
+// Write writes a single record. Flush must be called after the last record.
+func (w *Writer) Write(record []float64) error {
+	return w.w.Write(record)
+}
+
+// WriteAll writes the headings, if any, and records of d, and flushes the
+// output
+func (w *Writer) WriteAll(d *Dataset) error {
+	return w.w.WriteAllSlice(d.Headings, d.Data)
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,96 @@
+package numcsv
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "github.com/btracey/numcsv"
+)
+
+func TestReadEOF(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n1,2\n3,4\n"))
+	headings, err := r.Headings()
+	if err != nil || !reflect.DeepEqual(headings, []string{"a", "b"}) {
+		t.Fatalf("Headings = %q, %v", headings, err)
+	}
+	var got [][]float64
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, record)
+	}
+	if want := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %v, want %v", got, want)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read after EOF: err = %v, want io.EOF", err)
+	}
+
+	r = NewReader(strings.NewReader("a,b\n"))
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("no records: err = %v, want io.EOF", err)
+	}
+}
+
+func TestFromV1(t *testing.T) {
+	old := v1.NewReader(strings.NewReader("a,b\n1,2\n3,4\n"))
+	if _, err := old.ReadHeading(); err != nil {
+		t.Fatal(err)
+	}
+	r := FromV1(old)
+	if r.V1() != old {
+		t.Errorf("V1 does not return the original Reader")
+	}
+	d, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Dataset{Headings: []string{"a", "b"}, Data: [][]float64{{1, 2}, {3, 4}}}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("ReadAll = %+v, want %+v", d, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := &Dataset{
+		Headings: []string{"x", "y"},
+		Data:     [][]float64{{0.1, 1e-300}, {-3, 2.5e10}},
+	}
+	var b bytes.Buffer
+	if err := NewWriter(&b).WriteAll(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if r, c := got.Dims(); r != 2 || c != 2 {
+		t.Errorf("Dims = %d, %d, want 2, 2", r, c)
+	}
+
+	b.Reset()
+	w := NewWriter(&b, WithDelimiter(";"))
+	w.WriteHeading([]string{"x"})
+	w.Write([]float64{1.5})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	got, err = NewReader(&b, WithComma(";")).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col := got.Column("x"); !reflect.DeepEqual(col, []float64{1.5}) {
+		t.Errorf("Column(x) = %v, want [1.5]", col)
+	}
+}
@@ -0,0 +1,275 @@
+package numcsv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// xlsxComma separates the cells of a worksheet in the lines given to the
+// Reader. It is the ASCII unit separator, which does not appear in cells.
+const xlsxComma = "\x1f"
+
+// xlsxMissing is the field of an empty cell. It is always one of the
+// NaNTokens of the Reader, so empty cells are missing values.
+const xlsxMissing = "\x1e"
+
+var errXLSX = errors.New("not a supported .xlsx file")
+
+// xlsxCleaner removes the line breaks and separators from the cells
+var xlsxCleaner = strings.NewReplacer("\r", " ", "\n", " ", xlsxComma, " ", xlsxMissing, " ")
+
+// NewXLSXReader returns a Reader for the cells of the worksheet with the given
+// name in an Excel .xlsx workbook of the given size. If sheet is "", the
+// first worksheet is read. Each row of the worksheet is read as a line of a
+// CSV, so ReadHeading, Read, ReadAllSlice and the other methods work as for a
+// CSV file, configured by the options. The Comma and Splitter of the Reader
+// are set to read the cells. Empty cells and Excel errors such as #N/A are
+// missing values, in addition to any NaNTokens set by the options, and empty
+// heading cells are read as empty headings.
+func NewXLSXReader(r io.ReaderAt, size int64, sheet string, opts ...Option) (*Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	name, err := xlsxSheetPath(files, sheet)
+	if err != nil {
+		return nil, err
+	}
+	var strs []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if strs, err = xlsxSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", errXLSX, name)
+	}
+	text, err := xlsxSheetText(f, strs)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := NewReader(bytes.NewReader(text), opts...)
+	tokens := append([]string{xlsxMissing}, excelErrors...)
+	rd.NaNTokens = append(tokens, rd.NaNTokens...)
+	rd.Comma = xlsxComma
+	rd.HeadingComma = xlsxComma
+	rd.Splitter = nil
+	return rd, nil
+}
+
+// OpenXLSX returns a Reader for a worksheet of the .xlsx file at path, as in
+// NewXLSXReader. The worksheet is read into memory, so the file is closed
+// before OpenXLSX returns.
+func OpenXLSX(path, sheet string, opts ...Option) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return NewXLSXReader(f, info.Size(), sheet, opts...)
+}
+
+func xlsxDecode(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// xlsxSheetPath returns the name in the archive of the worksheet with the
+// given name, or of the first worksheet if name is ""
+func xlsxSheetPath(files map[string]*zip.File, name string) (string, error) {
+	wb, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("%w: missing xl/workbook.xml", errXLSX)
+	}
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xlsxDecode(wb, &workbook); err != nil {
+		return "", err
+	}
+	id := ""
+	for _, s := range workbook.Sheets {
+		if name == "" || s.Name == name {
+			id = s.ID
+			break
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: no sheet %q", errXLSX, name)
+	}
+
+	rels, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", fmt.Errorf("%w: missing xl/_rels/workbook.xml.rels", errXLSX)
+	}
+	var relationships struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xlsxDecode(rels, &relationships); err != nil {
+		return "", err
+	}
+	for _, rel := range relationships.Rels {
+		if rel.ID != id {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("%w: no worksheet for sheet %q", errXLSX, name)
+}
+
+// xlsxText is text that may be split into rich text runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	s := t.T
+	for _, r := range t.Runs {
+		s += r.T
+	}
+	return s
+}
+
+func xlsxSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := xlsxDecode(f, &sst); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		strs[i] = si.String()
+	}
+	return strs, nil
+}
+
+// xlsxSheetText returns the cells of the worksheet as lines of fields
+// separated by xlsxComma. Empty cells are xlsxMissing, and the rows are
+// padded with xlsxMissing to the width of the widest row.
+func xlsxSheetText(f *zip.File, strs []string) ([]byte, error) {
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xlsxDecode(f, &sheet); err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(sheet.Rows))
+	width := 0
+	for i, row := range sheet.Rows {
+		for _, c := range row.Cells {
+			j, err := xlsxColumn(c.Ref)
+			if err != nil {
+				return nil, err
+			}
+			if j < 0 {
+				j = len(rows[i])
+			}
+			for len(rows[i]) < j {
+				rows[i] = append(rows[i], xlsxMissing)
+			}
+			v := c.Value
+			switch c.Type {
+			case "s":
+				var k int
+				if _, err := fmt.Sscan(c.Value, &k); err != nil || k < 0 || k >= len(strs) {
+					return nil, fmt.Errorf("%w: shared string %q", errXLSX, c.Value)
+				}
+				v = strs[k]
+			case "inlineStr":
+				v = c.Inline.String()
+			}
+			v = xlsxCleaner.Replace(strings.TrimSpace(v))
+			if v == "" {
+				v = xlsxMissing
+			}
+			if j < len(rows[i]) {
+				rows[i][j] = v
+			} else {
+				rows[i] = append(rows[i], v)
+			}
+		}
+		if len(rows[i]) > width {
+			width = len(rows[i])
+		}
+	}
+	var buf bytes.Buffer
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		for len(row) < width {
+			row = append(row, xlsxMissing)
+		}
+		buf.WriteString(strings.Join(row, xlsxComma))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// xlsxMaxColumns is the number of columns of a worksheet, up to column XFD
+const xlsxMaxColumns = 16384
+
+// xlsxColumn returns the index of the column of a cell reference such as
+// "AB12". The letters may be in either case. If the reference has no letters
+// -1 is returned, and the cell is in the column after the previous cell.
+func xlsxColumn(ref string) (int, error) {
+	j := 0
+	for _, c := range ref {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			j = j*26 + int(c-'A'+1)
+		case c >= 'a' && c <= 'z':
+			j = j*26 + int(c-'a'+1)
+		default:
+			return j - 1, nil
+		}
+		if j > xlsxMaxColumns {
+			return 0, fmt.Errorf("%w: cell reference %q", errXLSX, ref)
+		}
+	}
+	return j - 1, nil
+}
@@ -0,0 +1,200 @@
+package numcsv
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// xlsxFile returns a workbook holding the given files, the workbook and its
+// relationships. sheets holds the name of each worksheet and its target,
+// which is relative to xl/ unless it starts with "/".
+func xlsxFile(t *testing.T, sheets [][2]string, files map[string]string) []byte {
+	t.Helper()
+	workbook := `<?xml version="1.0" encoding="UTF-8"?><workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	rels := `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	for i, s := range sheets {
+		id := "rId" + string(rune('1'+i))
+		workbook += `<sheet name="` + s[0] + `" sheetId="1" r:id="` + id + `"/>`
+		rels += `<Relationship Id="` + id + `" Type="worksheet" Target="` + s[1] + `"/>`
+	}
+	workbook += `</sheets></workbook>`
+	rels += `</Relationships>`
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	add := func(name, body string) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	add("xl/workbook.xml", workbook)
+	add("xl/_rels/workbook.xml.rels", rels)
+	for name, body := range files {
+		add(name, body)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func worksheet(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+// testWorkbook has a notes sheet and a data sheet with shared strings,
+// inline strings, empty cells, a short row, a skipped row and an error value
+func testWorkbook(t *testing.T) []byte {
+	return xlsxFile(t,
+		[][2]string{{"Notes", "worksheets/sheet1.xml"}, {"Data", "/xl/worksheets/sheet2.xml"}},
+		map[string]string{
+			"xl/sharedStrings.xml":     `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>time</t></si><si><r><t>veloc</t></r><r><t>ity (m/s)</t></r></si><si><t>NA</t></si></sst>`,
+			"xl/worksheets/sheet1.xml": worksheet(`<row r="1"><c r="A1" t="inlineStr"><is><t>hello</t></is></c></row>`),
+			"xl/worksheets/sheet2.xml": worksheet(`
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>ok</t></is></c></row>
+<row r="2"><c r="A2"><v>0.5</v></c><c r="B2"><v>3.25</v></c><c r="C2" t="b"><v>1</v></c></row>
+<row r="4"><c r="A4"><v>1</v></c><c r="C4" t="e"><v>#N/A</v></c></row>
+<row r="5"><c r="A5"><v>2</v></c><c r="B5" t="s"><v>2</v></c></row>
+<row r="6"><c r="A6"><v>3</v></c><c r="B6"><v></v></c><c r="C6"><v>0</v></c></row>
+`),
+		})
+}
+
+func TestXLSXReader(t *testing.T) {
+	file := testWorkbook(t)
+	r, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), "Data", WithUnits(), WithNaNTokens("NA"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headings, data, mask, err := readAllMaskedWithHeading(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"time", "velocity", "ok"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if unit := r.Units()[1]; unit != "m/s" {
+		t.Errorf("unit = %q, want %q", unit, "m/s")
+	}
+	nan := math.NaN()
+	want := [][]float64{{0.5, 3.25, 1}, {1, nan, nan}, {2, nan, nan}, {3, nan, 0}}
+	if !equalBits(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	wantMask := [][]bool{{true, true, true}, {true, false, false}, {true, false, false}, {true, false, true}}
+	if !reflect.DeepEqual(mask, wantMask) {
+		t.Errorf("mask = %v, want %v", mask, wantMask)
+	}
+}
+
+func readAllMaskedWithHeading(r *Reader) ([]string, [][]float64, [][]bool, error) {
+	if _, err := r.ReadHeading(); err != nil {
+		return nil, nil, nil, err
+	}
+	data, mask, err := r.ReadAllMasked()
+	return r.DataHeadings(), data, mask, err
+}
+
+func TestXLSXEmptyHeading(t *testing.T) {
+	file := xlsxFile(t,
+		[][2]string{{"Data", "worksheets/sheet1.xml"}},
+		map[string]string{
+			"xl/worksheets/sheet1.xml": worksheet(`<row r="1"><c r="A1" t="inlineStr"><is><t>a</t></is></c><c r="C1" t="inlineStr"><is><t>c</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="B2"><v>2</v></c><c r="C2"><v>3</v></c></row>`),
+		})
+	r, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headings, data, err := r.ReadAllWithHeading()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "", "c"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("headings = %q, want %q", headings, want)
+	}
+	if want := [][]float64{{1, 2, 3}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestXLSXSheets(t *testing.T) {
+	file := testWorkbook(t)
+	r, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headings, err := r.ReadHeading()
+	if err != nil || !reflect.DeepEqual(headings, []string{"hello"}) {
+		t.Errorf("first sheet headings = %q, %v, want [hello]", headings, err)
+	}
+	if _, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), "Nope"); !errors.Is(err, errXLSX) {
+		t.Errorf("missing sheet: err = %v, want errXLSX", err)
+	}
+}
+
+func TestXLSXErrors(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		sheets [][2]string
+		files  map[string]string
+	}{
+		{"missing worksheet", [][2]string{{"S", "worksheets/sheet1.xml"}}, nil},
+		{"bad shared string", [][2]string{{"S", "worksheets/sheet1.xml"}}, map[string]string{
+			"xl/worksheets/sheet1.xml": worksheet(`<row r="1"><c r="A1" t="s"><v>3</v></c></row>`),
+		}},
+		{"column out of range", [][2]string{{"S", "worksheets/sheet1.xml"}}, map[string]string{
+			"xl/worksheets/sheet1.xml": worksheet(`<row r="1"><c r="ZZZZZZZ1"><v>1</v></c></row>`),
+		}},
+		{"bad xml", [][2]string{{"S", "worksheets/sheet1.xml"}}, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row>`,
+		}},
+	} {
+		file := xlsxFile(t, test.sheets, test.files)
+		if _, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), ""); err == nil {
+			t.Errorf("%s: no error", test.name)
+		}
+	}
+	if _, err := NewXLSXReader(bytes.NewReader([]byte("not a zip")), 9, ""); err == nil {
+		t.Errorf("not a zip: no error")
+	}
+}
+
+func TestXLSXCellRefs(t *testing.T) {
+	file := xlsxFile(t,
+		[][2]string{{"S", "worksheets/sheet1.xml"}},
+		map[string]string{
+			"xl/worksheets/sheet1.xml": worksheet(`<row r="1"><c r="a1"><v>1</v></c><c r="c1"><v>3</v></c></row>
+<row r="2"><c r="1"><v>4</v></c><c><v>5</v></c><c r="C2"><v>6</v></c></row>`),
+		})
+	r, err := NewXLSXReader(bytes.NewReader(file), int64(len(file)), "", WithNoHeading())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.ReadAllSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]float64{{1, math.NaN(), 3}, {4, 5, 6}}; !equalBits(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "B12": 1, "Z3": 25, "AA1": 26, "AB7": 27, "ZZ1": 701, "AAA1": 702, "a1": 0, "ab7": 27, "XFD1": 16383, "": -1, "1": -1} {
+		if got, err := xlsxColumn(ref); got != want || err != nil {
+			t.Errorf("xlsxColumn(%q) = %d, %v, want %d", ref, got, err, want)
+		}
+	}
+	for _, ref := range []string{"XFE1", "ZZZZZZZ1"} {
+		if _, err := xlsxColumn(ref); !errors.Is(err, errXLSX) {
+			t.Errorf("xlsxColumn(%q): err = %v, want errXLSX", ref, err)
+		}
+	}
+}